@@ -31,6 +31,9 @@ type Config struct {
 	ServerPort           int           `env:"SERVER_PORT" envDefault:"8888"`
 	ServerReadTimeout    time.Duration `env:"SERVER_READ_TIMEOUT"`
 	ServerWriteTimeout   time.Duration `env:"SERVER_WRITE_TIMEOUT"`
+	MetricsPort          int           `env:"METRICS_PORT" envDefault:"0"`
+	TracingEnabled       bool          `env:"TRACING_ENABLED" envDefault:"false"`
+	TracingSampleRatio   float64       `env:"TRACING_SAMPLE_RATIO" envDefault:"1"`
 	DomainFilter         []string      `env:"DOMAIN_FILTER" envDefault:""`
 	ExcludeDomains       []string      `env:"EXCLUDE_DOMAIN_FILTER" envDefault:""`
 	RegexDomainFilter    string        `env:"REGEXP_DOMAIN_FILTER" envDefault:""`
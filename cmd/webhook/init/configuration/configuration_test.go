@@ -32,6 +32,7 @@ func TestInit(t *testing.T) {
 
 	assert.Equal(t, "0.0.0.0", cfg.ServerHost)
 	assert.Equal(t, 8888, cfg.ServerPort)
+	assert.Equal(t, 0, cfg.MetricsPort)
 	assert.Equal(t, []string(nil), cfg.DomainFilter)
 	assert.Equal(t, []string(nil), cfg.ExcludeDomains)
 	assert.Equal(t, "", cfg.RegexDomainFilter)
@@ -39,6 +40,7 @@ func TestInit(t *testing.T) {
 
 	t.Setenv("SERVER_HOST", "testhost")
 	t.Setenv("SERVER_PORT", "9999")
+	t.Setenv("METRICS_PORT", "9090")
 	t.Setenv("DOMAIN_FILTER", "test.com,test2.com")
 	t.Setenv("EXCLUDE_DOMAIN_FILTER", "exclude.com,exclude2.com")
 	t.Setenv("REGEXP_DOMAIN_FILTER", ".*test.*")
@@ -49,6 +51,7 @@ func TestInit(t *testing.T) {
 	cfg = Init()
 	assert.Equal(t, "testhost", cfg.ServerHost)
 	assert.Equal(t, 9999, cfg.ServerPort)
+	assert.Equal(t, 9090, cfg.MetricsPort)
 	assert.Equal(t, []string{"test.com", "test2.com"}, cfg.DomainFilter)
 	assert.Equal(t, []string{"exclude.com", "exclude2.com"}, cfg.ExcludeDomains)
 	assert.Equal(t, ".*test.*", cfg.RegexDomainFilter)
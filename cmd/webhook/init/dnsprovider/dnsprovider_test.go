@@ -57,6 +57,138 @@ func TestInit(t *testing.T) {
 				"INFOBLOX_VERSION":       "2.7.1",
 			},
 		},
+		{
+			name:   "host record mode",
+			config: configuration.Config{},
+			env: map[string]string{
+				"INFOBLOX_WAPI_USER":     "user123",
+				"INFOBLOX_WAPI_PASSWORD": "password",
+				"INFOBLOX_VERSION":       "2.7.1",
+				"INFOBLOX_RECORD_MODE":   "host",
+			},
+		},
+		{
+			name:   "invalid record mode",
+			config: configuration.Config{},
+			env: map[string]string{
+				"INFOBLOX_WAPI_USER":     "user123",
+				"INFOBLOX_WAPI_PASSWORD": "password",
+				"INFOBLOX_VERSION":       "2.7.1",
+				"INFOBLOX_RECORD_MODE":   "cname",
+			},
+			expectedError: "invalid record mode",
+		},
+		{
+			name:   "batch mode",
+			config: configuration.Config{},
+			env: map[string]string{
+				"INFOBLOX_WAPI_USER":     "user123",
+				"INFOBLOX_WAPI_PASSWORD": "password",
+				"INFOBLOX_VERSION":       "2.7.1",
+				"INFOBLOX_BATCH_SIZE":    "100",
+			},
+		},
+		{
+			name:   "invalid batch size",
+			config: configuration.Config{},
+			env: map[string]string{
+				"INFOBLOX_WAPI_USER":     "user123",
+				"INFOBLOX_WAPI_PASSWORD": "password",
+				"INFOBLOX_VERSION":       "2.7.1",
+				"INFOBLOX_BATCH_SIZE":    "-1",
+			},
+			expectedError: "invalid batch size",
+		},
+		{
+			name:   "transactional and continue on error",
+			config: configuration.Config{},
+			env: map[string]string{
+				"INFOBLOX_WAPI_USER":         "user123",
+				"INFOBLOX_WAPI_PASSWORD":     "password",
+				"INFOBLOX_VERSION":           "2.7.1",
+				"INFOBLOX_TRANSACTIONAL":     "true",
+				"INFOBLOX_CONTINUE_ON_ERROR": "true",
+			},
+			expectedError: "mutually exclusive",
+		},
+		{
+			name:   "invalid request rate limit",
+			config: configuration.Config{},
+			env: map[string]string{
+				"INFOBLOX_WAPI_USER":          "user123",
+				"INFOBLOX_WAPI_PASSWORD":      "password",
+				"INFOBLOX_VERSION":            "2.7.1",
+				"INFOBLOX_REQUEST_RATE_LIMIT": "-5",
+			},
+			expectedError: "invalid request rate limit",
+		},
+		{
+			name:   "invalid write rate burst",
+			config: configuration.Config{},
+			env: map[string]string{
+				"INFOBLOX_WAPI_USER":        "user123",
+				"INFOBLOX_WAPI_PASSWORD":    "password",
+				"INFOBLOX_VERSION":          "2.7.1",
+				"INFOBLOX_WRITE_RATE_LIMIT": "5",
+				"INFOBLOX_WRITE_RATE_BURST": "0",
+			},
+			expectedError: "invalid write rate burst",
+		},
+		{
+			name:   "invalid cache TTL",
+			config: configuration.Config{},
+			env: map[string]string{
+				"INFOBLOX_WAPI_USER":     "user123",
+				"INFOBLOX_WAPI_PASSWORD": "password",
+				"INFOBLOX_VERSION":       "2.7.1",
+				"INFOBLOX_CACHE_TTL":     "-1m",
+			},
+			expectedError: "invalid cache TTL",
+		},
+		{
+			name:   "incremental sync without cache",
+			config: configuration.Config{},
+			env: map[string]string{
+				"INFOBLOX_WAPI_USER":        "user123",
+				"INFOBLOX_WAPI_PASSWORD":    "password",
+				"INFOBLOX_VERSION":          "2.7.1",
+				"INFOBLOX_INCREMENTAL_SYNC": "true",
+			},
+			expectedError: "incremental sync requires the records cache",
+		},
+		{
+			name:   "negative page size",
+			config: configuration.Config{},
+			env: map[string]string{
+				"INFOBLOX_WAPI_USER":     "user123",
+				"INFOBLOX_WAPI_PASSWORD": "password",
+				"INFOBLOX_VERSION":       "2.7.1",
+				"INFOBLOX_PAGE_SIZE":     "-1",
+			},
+			expectedError: "invalid paging configuration",
+		},
+		{
+			name:   "circuit breaker without timeout",
+			config: configuration.Config{},
+			env: map[string]string{
+				"INFOBLOX_WAPI_USER":               "user123",
+				"INFOBLOX_WAPI_PASSWORD":           "password",
+				"INFOBLOX_VERSION":                 "2.7.1",
+				"INFOBLOX_CIRCUIT_BREAKER_TIMEOUT": "0s",
+			},
+			expectedError: "invalid circuit breaker configuration",
+		},
+		{
+			name:   "zero readiness check interval",
+			config: configuration.Config{},
+			env: map[string]string{
+				"INFOBLOX_WAPI_USER":                "user123",
+				"INFOBLOX_WAPI_PASSWORD":            "password",
+				"INFOBLOX_VERSION":                  "2.7.1",
+				"INFOBLOX_READINESS_CHECK_INTERVAL": "0s",
+			},
+			expectedError: "invalid readiness check interval",
+		},
 		{
 			name:          "empty configuration",
 			config:        configuration.Config{},
@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	log "github.com/sirupsen/logrus"
 
@@ -43,22 +44,50 @@ import (
 // - /records (GET): returns the current records
 // - /records (POST): applies the changes
 // - /adjustendpoints (POST): executes the AdjustEndpoints method
+// - /refresh (POST): refreshes the cached records
+// - /metrics (GET): returns the Prometheus metrics, unless they are served on a port of their own
+// - /healthz (GET): returns 503 while the provider is degraded
+// - /livez (GET): liveness probe, returns 200 while the process serves requests
+// - /readyz (GET): readiness probe, returns 503 until the readiness checks of the provider succeed
 func Init(config configuration.Config, p *webhook.Webhook) *http.Server {
 	r := chi.NewRouter()
-	r.Use(webhook.Health)
+	r.Use(p.Health)
+	r.Use(instrument)
 	r.Get("/", p.Negotiate)
 	r.Get("/records", p.Records)
 	r.Post("/records", p.ApplyChanges)
 	r.Post("/adjustendpoints", p.AdjustEndpoints)
+	r.Post("/refresh", p.Refresh)
+	if config.MetricsPort == 0 {
+		r.Method(http.MethodGet, "/metrics", promhttp.Handler())
+	}
 
 	srv := createHTTPServer(fmt.Sprintf("%s:%d", config.ServerHost, config.ServerPort), r, config.ServerReadTimeout, config.ServerWriteTimeout)
+	serve(srv)
+	return srv
+}
+
+// InitMetrics starts the server of the Prometheus metrics on the metrics port, if one is configured.
+// It returns nil otherwise, the metrics are served by the webhook server then.
+func InitMetrics(config configuration.Config) *http.Server {
+	if config.MetricsPort == 0 {
+		return nil
+	}
+	r := chi.NewRouter()
+	r.Method(http.MethodGet, "/metrics", promhttp.Handler())
+
+	srv := createHTTPServer(fmt.Sprintf("%s:%d", config.ServerHost, config.MetricsPort), r, config.ServerReadTimeout, config.ServerWriteTimeout)
+	serve(srv)
+	return srv
+}
+
+func serve(srv *http.Server) {
 	go func() {
 		log.Infof("starting server on addr: '%s' ", srv.Addr)
 		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Errorf("can't serve on addr: '%s', error: %v", srv.Addr, err)
 		}
 	}()
-	return srv
 }
 
 func createHTTPServer(addr string, hand http.Handler, readTimeout, writeTimeout time.Duration) *http.Server {
@@ -70,15 +99,20 @@ func createHTTPServer(addr string, hand http.Handler, readTimeout, writeTimeout
 	}
 }
 
-// ShutdownGracefully gracefully shutdown the http server
-func ShutdownGracefully(srv *http.Server) {
+// ShutdownGracefully gracefully shutdown the http servers, nil servers are skipped
+func ShutdownGracefully(servers ...*http.Server) {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 	sig := <-sigCh
 	log.Infof("shutting down server due to received signal: %v", sig)
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Errorf("error shutting down server: %v", err)
+	for _, srv := range servers {
+		if srv == nil {
+			continue
+		}
+		if err := srv.Shutdown(ctx); err != nil {
+			log.Errorf("error shutting down server: %v", err)
+		}
 	}
 	cancel()
 }
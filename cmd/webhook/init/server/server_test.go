@@ -40,6 +40,7 @@ type testCase struct {
 	returnRecords             []*endpoint.Endpoint
 	returnAdjustedEndpoints   []*endpoint.Endpoint
 	returnDomainFilter        endpoint.DomainFilter
+	returnReadiness           interface{}
 	hasError                  error
 	method                    string
 	path                      string
@@ -439,6 +440,180 @@ func TestAdjustEndpoints(t *testing.T) {
 	executeTestCases(t, testCases)
 }
 
+func TestRefresh(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:               "valid case",
+			method:             http.MethodPost,
+			path:               "/refresh",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			name:               "backend error",
+			hasError:           fmt.Errorf("backend error"),
+			method:             http.MethodPost,
+			path:               "/refresh",
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponseHeaders: map[string]string{
+				"Content-Type": "text/plain",
+			},
+			expectedBody: "backend error",
+		},
+	}
+
+	executeTestCases(t, testCases)
+}
+
+func TestMetrics(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:               "valid case",
+			method:             http.MethodGet,
+			path:               "/metrics",
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+
+	executeTestCases(t, testCases)
+}
+
+func TestRequestMetrics(t *testing.T) {
+	executeTestCases(t, []testCase{
+		{
+			name:               "records",
+			method:             http.MethodGet,
+			headers:            map[string]string{"Accept": "application/external.dns.webhook+json;version=1"},
+			path:               "/records",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "unknown path",
+			method:             http.MethodGet,
+			path:               "/unknown",
+			expectedStatusCode: http.StatusNotFound,
+		},
+	})
+
+	response, err := http.Get("http://localhost:8888/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = response.Body.Close()
+	for _, expected := range []string{
+		`webhook_http_request_duration_seconds_count{code="200",method="GET",route="/records"}`,
+		`webhook_http_request_duration_seconds_count{code="404",method="GET",route="unmatched"}`,
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("expected metric '%s' in the metrics", expected)
+		}
+	}
+}
+
+func TestInitMetrics(t *testing.T) {
+	if srv := InitMetrics(configuration.Config{}); srv != nil {
+		t.Errorf("expected no metrics server without a metrics port")
+	}
+
+	srv := InitMetrics(configuration.Config{ServerHost: "localhost", MetricsPort: 8889})
+	if srv == nil {
+		t.Fatal("expected a metrics server on the metrics port")
+	}
+	defer func() { _ = srv.Shutdown(context.TODO()) }()
+
+	var response *http.Response
+	var err error
+	for i := 0; i < 100; i++ {
+		if response, err = http.Get("http://localhost:8889/metrics"); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("expected status code %d, got %d", http.StatusOK, response.StatusCode)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:               "healthy",
+			method:             http.MethodGet,
+			path:               "/healthz",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "degraded",
+			hasError:           fmt.Errorf("the circuit breaker is open"),
+			method:             http.MethodGet,
+			path:               "/healthz",
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedResponseHeaders: map[string]string{
+				"Content-Type": "text/plain",
+			},
+			expectedBody: "the circuit breaker is open",
+		},
+	}
+
+	executeTestCases(t, testCases)
+}
+
+func TestProbes(t *testing.T) {
+	testCases := []testCase{
+		{
+			name:               "live",
+			hasError:           fmt.Errorf("the circuit breaker is open"),
+			method:             http.MethodGet,
+			path:               "/livez",
+			expectedStatusCode: http.StatusOK,
+			expectedResponseHeaders: map[string]string{
+				"Content-Type": "application/json",
+			},
+			expectedBody: `{"status":"ok"}`,
+		},
+		{
+			name:               "ready",
+			returnReadiness:    map[string]bool{"wapi": true},
+			method:             http.MethodGet,
+			path:               "/readyz",
+			expectedStatusCode: http.StatusOK,
+			expectedResponseHeaders: map[string]string{
+				"Content-Type": "application/json",
+			},
+			expectedBody: `{"status":"ready","checks":{"wapi":true}}`,
+		},
+		{
+			name:               "not ready",
+			returnReadiness:    map[string]bool{"wapi": false},
+			hasError:           fmt.Errorf("wapi: 401 Unauthorized"),
+			method:             http.MethodGet,
+			path:               "/readyz",
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedResponseHeaders: map[string]string{
+				"Content-Type": "application/json",
+			},
+			expectedBody: `{"status":"not ready","error":"wapi: 401 Unauthorized","checks":{"wapi":false}}`,
+		},
+		{
+			name:               "not checked yet",
+			hasError:           fmt.Errorf("readiness not checked yet"),
+			method:             http.MethodGet,
+			path:               "/readyz",
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody:       `{"status":"not ready","error":"readiness not checked yet"}`,
+		},
+	}
+
+	executeTestCases(t, testCases)
+}
+
 func TestNegotiate(t *testing.T) {
 	testCases := []testCase{
 		{
@@ -551,6 +726,20 @@ func (d *MockProvider) ApplyChanges(_ context.Context, changes *plan.Changes) er
 	return nil
 }
 
+func (d *MockProvider) Refresh(_ context.Context) error {
+	return d.testCase.hasError
+}
+
+func (d *MockProvider) Health() error {
+	return d.testCase.hasError
+}
+
+func (d *MockProvider) RunReadinessChecks(context.Context) {}
+
+func (d *MockProvider) Readiness() (interface{}, error) {
+	return d.testCase.returnReadiness, d.testCase.hasError
+}
+
 func (d *MockProvider) AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]*endpoint.Endpoint, error) {
 	if !reflect.DeepEqual(endpoints, d.testCase.expectedEndpointsToAdjust) {
 		d.t.Errorf("expected endpoints to adjust '%v', got '%v'", d.testCase.expectedEndpointsToAdjust, endpoints)
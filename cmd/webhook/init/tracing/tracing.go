@@ -0,0 +1,79 @@
+package tracing
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/AbsaOSS/external-dns-infoblox-webhook/cmd/webhook/init/configuration"
+)
+
+const serviceName = "external-dns-infoblox-webhook"
+
+// Init sets up the export of the traces over OTLP/HTTP, if tracing is enabled. The exporter is
+// configured by the standard OTEL_EXPORTER_OTLP_* environment variables, e.g. the endpoint of
+// the collector, and the resource by OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES. The trace
+// context of the requests of external-dns is propagated in the W3C format. The returned function
+// flushes the pending spans and shuts the exporter down.
+func Init(ctx context.Context, config configuration.Config, version string) (func(context.Context) error, error) {
+	if !config.TracingEnabled {
+		return func(context.Context) error { return nil }, nil
+	}
+	if config.TracingSampleRatio < 0 || config.TracingSampleRatio > 1 {
+		return nil, fmt.Errorf("invalid tracing sample ratio %g: expected a ratio between 0 and 1", config.TracingSampleRatio)
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not create the trace exporter: %w", err)
+	}
+	// the attributes of the environment take precedence
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			attribute.String("service.name", serviceName),
+			attribute.String("service.version", version),
+		),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create the trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		// the traces of external-dns keep their sampling decision
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.TracingSampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	log.Infof("tracing enabled, sample ratio %g", config.TracingSampleRatio)
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,147 @@
+package tracing
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+
+	"github.com/AbsaOSS/external-dns-infoblox-webhook/cmd/webhook/init/configuration"
+	"github.com/AbsaOSS/external-dns-infoblox-webhook/pkg/webhook"
+)
+
+const (
+	traceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+	parentSpanID = "00f067aa0ba902b7"
+)
+
+// collector is a local OTLP/HTTP collector, it keeps the spans it receives
+type collector struct {
+	mu    sync.Mutex
+	spans []*tracepb.Span
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil || r.URL.Path != "/v1/traces" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	req := &collectortrace.ExportTraceServiceRequest{}
+	if err = proto.Unmarshal(body, req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, resourceSpans := range req.GetResourceSpans() {
+		for _, scopeSpans := range resourceSpans.GetScopeSpans() {
+			c.spans = append(c.spans, scopeSpans.GetSpans()...)
+		}
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *collector) span(name string) *tracepb.Span {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, span := range c.spans {
+		if span.GetName() == name {
+			return span
+		}
+	}
+	return nil
+}
+
+// tracedProvider starts a span of its own while fetching the records
+type tracedProvider struct {
+	provider.BaseProvider
+}
+
+func (p *tracedProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	_, span := otel.Tracer("test").Start(ctx, "infoblox.zones")
+	span.End()
+	return []*endpoint.Endpoint{}, nil
+}
+
+func (p *tracedProvider) ApplyChanges(context.Context, *plan.Changes) error {
+	return nil
+}
+
+func TestInit(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", server.URL)
+	t.Cleanup(func() {
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+
+	shutdown, err := Init(context.Background(), configuration.Config{TracingEnabled: true, TracingSampleRatio: 1}, "test")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/records", nil)
+	req.Header.Set("Accept", "application/external.dns.webhook+json;version=1")
+	req.Header.Set("traceparent", "00-"+traceID+"-"+parentSpanID+"-01")
+	rec := httptest.NewRecorder()
+	webhook.New(&tracedProvider{}).Records(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// the spans are flushed to the collector on shutdown
+	require.NoError(t, shutdown(context.Background()))
+
+	handler := c.span("GET /records")
+	require.NotNil(t, handler)
+	assert.Equal(t, traceID, hex.EncodeToString(handler.GetTraceId()))
+	assert.Equal(t, parentSpanID, hex.EncodeToString(handler.GetParentSpanId()))
+	assert.Equal(t, tracepb.Span_SPAN_KIND_SERVER, handler.GetKind())
+
+	zones := c.span("infoblox.zones")
+	require.NotNil(t, zones)
+	assert.Equal(t, traceID, hex.EncodeToString(zones.GetTraceId()))
+	assert.Equal(t, handler.GetSpanId(), zones.GetParentSpanId())
+}
+
+func TestInitDisabled(t *testing.T) {
+	shutdown, err := Init(context.Background(), configuration.Config{}, "test")
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestInitInvalidSampleRatio(t *testing.T) {
+	_, err := Init(context.Background(), configuration.Config{TracingEnabled: true, TracingSampleRatio: 2}, "test")
+	assert.ErrorContains(t, err, "invalid tracing sample ratio")
+}
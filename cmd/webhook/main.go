@@ -20,12 +20,14 @@ Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
 */
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/AbsaOSS/external-dns-infoblox-webhook/cmd/webhook/init/configuration"
 	"github.com/AbsaOSS/external-dns-infoblox-webhook/cmd/webhook/init/dnsprovider"
 	"github.com/AbsaOSS/external-dns-infoblox-webhook/cmd/webhook/init/logging"
 	"github.com/AbsaOSS/external-dns-infoblox-webhook/cmd/webhook/init/server"
+	"github.com/AbsaOSS/external-dns-infoblox-webhook/cmd/webhook/init/tracing"
 	"github.com/AbsaOSS/external-dns-infoblox-webhook/pkg/webhook"
 	log "github.com/sirupsen/logrus"
 )
@@ -54,11 +56,25 @@ func main() {
 	logging.Init()
 
 	config := configuration.Init()
+	shutdownTracing, err := tracing.Init(context.Background(), config, Version)
+	if err != nil {
+		log.Fatalf("failed to initialize tracing: %v", err)
+	}
+
 	provider, err := dnsprovider.Init(config)
 	if err != nil {
 		log.Fatalf("failed to initialize provider: %v", err)
 	}
 
-	srv := server.Init(config, webhook.New(provider))
-	server.ShutdownGracefully(srv)
+	ctx, cancel := context.WithCancel(context.Background())
+	wh := webhook.New(provider)
+	wh.StartReadinessChecks(ctx)
+
+	srv := server.Init(config, wh)
+	metricsSrv := server.InitMetrics(config)
+	server.ShutdownGracefully(srv, metricsSrv)
+	cancel()
+	if err = shutdownTracing(context.Background()); err != nil {
+		log.Errorf("error flushing traces: %v", err)
+	}
 }
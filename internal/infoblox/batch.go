@@ -0,0 +1,236 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// batchRefState is the state variable the _ref of the object found by a GET inside a
+// multi-request is assigned to, the following DELETE or PUT of the batch refers to it
+const batchRefState = "ref"
+
+// multiRequestConnector is an IBConnector which can send WAPI multi-requests, i.e. several
+// requests in a single POST to the request object. Infoblox processes a multi-request
+// as a whole: if one of its requests fails, none of them is applied.
+type multiRequestConnector interface {
+	ibclient.IBConnector
+	SendMultiRequest(req *ibclient.MultiRequest) ([]map[string]interface{}, error)
+}
+
+// batchEnabled reports whether the changes are sent as WAPI multi-requests. In transactional
+// mode the changes are applied one by one, as each of them is journaled.
+func (p *Provider) batchEnabled() bool {
+	_, ok := p.client.(multiRequestConnector)
+	return ok && p.config.BatchSize > 0 && !p.config.Transactional
+}
+
+// submitBatches applies the changes of a zone in multi-requests of at most BatchSize changes.
+// Host record changes add or remove addresses of the existing Host record and are applied
+// one by one. When a batch fails, its changes are applied one by one as well, so the error
+// is reported for the change which caused it. In continue on error mode, the errors of single
+// changes are added to the report. Creates which depend on a delete of an earlier batch which
+// failed are skipped, see scheduleChanges.
+func (p *Provider) submitBatches(ctx context.Context, scheduled zoneChanges, report *ChangesError) error {
+	batch := make([]*infobloxChange, 0, p.config.BatchSize)
+	for _, change := range scheduled.changes {
+		if p.isHostRecordEndpoint(change.Endpoint) || scheduled.failedConflict(change) != nil {
+			if err := report.collect(p.submitScheduled(ctx, scheduled, change, nil)); err != nil {
+				return err
+			}
+			continue
+		}
+		batch = append(batch, change)
+		if len(batch) == p.config.BatchSize {
+			if err := p.submitBatch(ctx, scheduled, batch, report); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if len(batch) > 0 {
+		return p.submitBatch(ctx, scheduled, batch, report)
+	}
+	return nil
+}
+
+func (p *Provider) submitBatch(ctx context.Context, scheduled zoneChanges, batch []*infobloxChange, report *ChangesError) (err error) {
+	zone := scheduled.zone
+	ctx, span := startSpan(ctx, "infoblox.batch", attribute.String("infoblox.zone", zone), attribute.Int("infoblox.changes", len(batch)))
+	defer func() { endSpan(span, err) }()
+	var body []*ibclient.RequestBody
+	changes := make([]*infobloxChange, 0, len(batch))
+	for _, change := range batch {
+		requests, err := p.batchRequests(ctx, change)
+		if err != nil {
+			scheduled.fail(change)
+			if err = report.collect(newChangeError(zone, change, err)); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(requests) == 0 {
+			continue
+		}
+		body = append(body, requests...)
+		changes = append(changes, change)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	logFields := log.Fields{
+		"zone":     zone,
+		"changes":  len(changes),
+		"requests": len(body),
+	}
+	if p.config.DryRun {
+		for _, change := range changes {
+			log.WithFields(log.Fields{
+				"action": change.Action,
+				"record": change.Endpoint.DNSName,
+				"type":   change.Endpoint.RecordType,
+				"target": change.Endpoint.Targets,
+				"zone":   zone,
+			}).Info("Dry run: skipping..")
+		}
+		return nil
+	}
+	log.WithFields(logFields).Info("Sending batch")
+	if _, err := p.clientWithContext(ctx).(multiRequestConnector).SendMultiRequest(ibclient.NewMultiRequest(body)); err != nil {
+		log.WithFields(logFields).Warnf("Batch failed, applying its changes one by one: %s", err)
+		for _, change := range changes {
+			if err = report.collect(p.submitScheduled(ctx, scheduled, change, nil)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// batchRequests compiles a change into the requests of a multi-request. A create is a single
+// POST, a delete looks the object up by the search fields of recordSet and refers to its _ref
+// in the following DELETE. An update is resolved before, as submitChange does: the existing
+// object is fetched and updated by a PUT to its _ref if it drifted, an update of a missing
+// object is a create and an update of an object which is up to date has no requests.
+func (p *Provider) batchRequests(ctx context.Context, change *infobloxChange) ([]*ibclient.RequestBody, error) {
+	record, err := p.batchRecord(ctx, change)
+	if err != nil {
+		return nil, fmt.Errorf("could not build record: %w", err)
+	}
+	if change.Action == infobloxUpdate {
+		return p.batchUpdateRequests(ctx, change, record)
+	}
+	data, err := batchRequestData(record.obj)
+	if err != nil {
+		return nil, err
+	}
+	if change.Action == infobloxCreate {
+		return []*ibclient.RequestBody{{
+			Method: "POST",
+			Object: record.obj.ObjectType(),
+			Data:   data,
+		}}, nil
+	}
+
+	searchFields := map[string]interface{}{}
+	for name, value := range recordSearchFields(record.obj) {
+		searchFields[name] = value
+	}
+	lookup := &ibclient.RequestBody{
+		Method:      "GET",
+		Object:      record.obj.ObjectType(),
+		Data:        searchFields,
+		AssignState: map[string]string{batchRefState: "_ref"},
+		Discard:     true,
+	}
+	if change.Action != infobloxDelete {
+		return nil, fmt.Errorf("unknown action '%s'", change.Action)
+	}
+	return []*ibclient.RequestBody{lookup, {
+		Method:             "DELETE",
+		Object:             fmt.Sprintf("##STATE:%s:##", batchRefState),
+		EnableSubstitution: true,
+	}}, nil
+}
+
+// batchUpdateRequests fetches the object of an update and compiles the requests which bring
+// it up to date
+func (p *Provider) batchUpdateRequests(ctx context.Context, change *infobloxChange, record infobloxRecordSet) ([]*ibclient.RequestBody, error) {
+	err := p.clientWithContext(ctx).GetObject(record.obj, "", ibclient.NewQueryParams(false, recordSearchFields(record.obj)), record.res)
+	if err != nil && !isNotFoundError(err) {
+		return nil, fmt.Errorf("could not fetch %s record: %w", change.Endpoint.RecordType, err)
+	}
+	refId, logFields, err := getRefID(&record)
+	if err != nil {
+		return nil, err
+	}
+	if refId == "" {
+		// the object to update is missing in Infoblox, e.g. the PTR record of an existing A record
+		return p.batchRequests(ctx, &infobloxChange{Action: infobloxCreate, Endpoint: change.Endpoint})
+	}
+	if !recordDrifted(&record) {
+		log.WithFields(logFields).Debug("Record is up to date, skipping..")
+		return nil, nil
+	}
+	data, err := batchRequestData(record.obj)
+	if err != nil {
+		return nil, err
+	}
+	// Infoblox refuses the view on updates, see recordSet
+	delete(data, "view")
+	return []*ibclient.RequestBody{{
+		Method: "PUT",
+		Object: refId,
+		Data:   data,
+	}}, nil
+}
+
+// batchRecord builds the record of a change without fetching it, the multi-request looks it
+// up itself. The glue addresses of NS records are only resolved for creates, a delete or update
+// finds the record by its name and name server, as submitChange does.
+func (p *Provider) batchRecord(ctx context.Context, change *infobloxChange) (infobloxRecordSet, error) {
+	if change.Action == infobloxCreate || change.Endpoint.RecordType != endpoint.RecordTypeNS {
+		return p.recordSet(ctx, change.Endpoint, false)
+	}
+	obj := newEmptyRecordNS()
+	obj.Name = change.Endpoint.DNSName
+	obj.Nameserver = &change.Endpoint.Targets[0]
+	return infobloxRecordSet{obj: obj, res: &[]ibclient.RecordNS{}}, nil
+}
+
+// batchRequestData returns the fields of the object as they are sent by the Infoblox client
+func batchRequestData(obj ibclient.IBObject) (map[string]interface{}, error) {
+	body := (&ibclient.WapiRequestBuilder{}).BuildBody(ibclient.CREATE, obj)
+	if body == nil {
+		return nil, fmt.Errorf("could not serialize %s object", obj.ObjectType())
+	}
+	data := map[string]interface{}{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("could not serialize %s object: %w", obj.ObjectType(), err)
+	}
+	return data, nil
+}
@@ -0,0 +1,241 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+)
+
+func (client *mockIBConnector) SendMultiRequest(req *ibclient.MultiRequest) ([]map[string]interface{}, error) {
+	client.multiRequests = append(client.multiRequests, req)
+	if client.multiRequestErr != nil {
+		return nil, client.multiRequestErr
+	}
+	return []map[string]interface{}{}, nil
+}
+
+func newBatchTestClient() *mockIBConnector {
+	return &mockIBConnector{
+		mockInfobloxZones: &[]ibclient.ZoneAuth{
+			createMockInfobloxZone("example.com"),
+		},
+		mockInfobloxObjects: &[]ibclient.IBObject{
+			createMockInfobloxObjectWithZone("old.example.com", endpoint.RecordTypeA, "1.1.1.1", "example.com"),
+		},
+	}
+}
+
+func TestInfobloxApplyChangesBatch(t *testing.T) {
+	client := newBatchTestClient()
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, client)
+	providerCfg.config.BatchSize = 2
+
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+			endpoint.NewEndpoint("b.example.com", endpoint.RecordTypeCNAME, "a.example.com"),
+			endpoint.NewEndpoint("c.example.com", endpoint.RecordTypeTXT, "tag"),
+		},
+		Delete: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("old.example.com", endpoint.RecordTypeA, "1.1.1.1"),
+		},
+	}
+	require.NoError(t, providerCfg.ApplyChanges(context.Background(), changes))
+
+	// nothing is sent outside of the multi-requests
+	assert.Empty(t, client.createdEndpoints)
+	assert.Empty(t, client.deletedEndpoints)
+	require.Len(t, client.multiRequests, 2)
+
+	// the delete is scheduled first
+	first := client.multiRequests[0].Body
+	require.Len(t, first, 3)
+	assert.Equal(t, &ibclient.RequestBody{
+		Method:      "GET",
+		Object:      recordA,
+		Data:        map[string]interface{}{"name": "old.example.com", "ipv4addr": "1.1.1.1"},
+		AssignState: map[string]string{"ref": "_ref"},
+		Discard:     true,
+	}, first[0])
+	assert.Equal(t, &ibclient.RequestBody{
+		Method:             "DELETE",
+		Object:             "##STATE:ref:##",
+		EnableSubstitution: true,
+	}, first[1])
+	assert.Equal(t, "POST", first[2].Method)
+	assert.Equal(t, recordA, first[2].Object)
+	assert.Equal(t, "a.example.com", first[2].Data["name"])
+	assert.Equal(t, "1.2.3.4", first[2].Data["ipv4addr"])
+
+	second := client.multiRequests[1].Body
+	require.Len(t, second, 2)
+	assert.Equal(t, "POST", second[0].Method)
+	assert.Equal(t, recordCname, second[0].Object)
+	assert.Equal(t, "POST", second[1].Method)
+	assert.Equal(t, recordTxt, second[1].Object)
+}
+
+func TestInfobloxApplyChangesBatchFallback(t *testing.T) {
+	client := newBatchTestClient()
+	client.multiRequestErr = errors.New("WAPI request error: 400 Bad Request")
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, client)
+	providerCfg.config.BatchSize = 10
+
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+		},
+		Delete: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("old.example.com", endpoint.RecordTypeA, "1.1.1.1"),
+		},
+	}
+	require.NoError(t, providerCfg.ApplyChanges(context.Background(), changes))
+
+	// the failed batch is applied change by change
+	assert.Len(t, client.multiRequests, 1)
+	validateEndpoints(t, client.createdEndpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+	})
+	validateEndpoints(t, client.deletedEndpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("old.example.com", endpoint.RecordTypeA, ""),
+	})
+}
+
+func TestInfobloxApplyChangesBatchChangeError(t *testing.T) {
+	client := newBatchTestClient()
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, client)
+	providerCfg.config.BatchSize = 10
+
+	invalid := endpoint.NewEndpoint("mail.example.com", endpoint.RecordTypeMX, "mx.example.com")
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+			invalid,
+		},
+	}
+	err := providerCfg.ApplyChanges(context.Background(), changes)
+
+	var changeErr *ChangeError
+	require.True(t, errors.As(err, &changeErr))
+	assert.Equal(t, invalid, changeErr.Endpoint)
+	assert.Equal(t, infobloxCreate, changeErr.Action)
+	assert.Equal(t, "example.com", changeErr.Zone)
+	assert.Empty(t, client.multiRequests)
+}
+
+func TestBatchRequestsUpdate(t *testing.T) {
+	ttl, useTTL := uint32(300), true
+	existing := createMockInfobloxObjectWithZone("a.example.com", endpoint.RecordTypeA, "1.2.3.4", "example.com").(*ibclient.RecordA)
+	existing.Ttl, existing.UseTtl, existing.Ea = &ttl, &useTTL, ibclient.EA{"owner": "team-a"}
+	client := &mockIBConnector{mockInfobloxObjects: &[]ibclient.IBObject{existing}}
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "default", false, false, client)
+	providerCfg.config.ExtAttrsJSON = `{"owner": "team-a"}`
+
+	// the drifted record is updated by its reference
+	requests, err := providerCfg.batchRequests(context.Background(), &infobloxChange{
+		Action:   infobloxUpdate,
+		Endpoint: endpoint.NewEndpointWithTTL("a.example.com", endpoint.RecordTypeA, 600, "1.2.3.4"),
+	})
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.Equal(t, "PUT", requests[0].Method)
+	assert.Equal(t, existing.Ref, requests[0].Object)
+	// Infoblox refuses the view on updates
+	assert.NotContains(t, requests[0].Data, "view")
+	assert.Equal(t, float64(600), requests[0].Data["ttl"])
+	assert.Equal(t, map[string]interface{}{"owner": map[string]interface{}{"value": "team-a"}}, requests[0].Data["extattrs"])
+
+	// the record which is up to date is not rewritten
+	requests, err = providerCfg.batchRequests(context.Background(), &infobloxChange{
+		Action:   infobloxUpdate,
+		Endpoint: endpoint.NewEndpointWithTTL("a.example.com", endpoint.RecordTypeA, 300, "1.2.3.4"),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, requests)
+
+	// the missing PTR record is created
+	requests, err = providerCfg.batchRequests(context.Background(), &infobloxChange{
+		Action:   infobloxUpdate,
+		Endpoint: endpoint.NewEndpointWithTTL("a.example.com", endpoint.RecordTypePTR, 600, "1.2.3.4"),
+	})
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.Equal(t, "POST", requests[0].Method)
+	assert.Equal(t, recordPtr, requests[0].Object)
+}
+
+func TestInfobloxApplyChangesBatchUpdatePTR(t *testing.T) {
+	client := newBatchTestClient()
+	*client.mockInfobloxZones = append(*client.mockInfobloxZones, createMockInfobloxZone("1.1.1.0/24"))
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com", "1.1.1.0/24"}), provider.NewZoneIDFilter([]string{""}), "", false, true, client)
+	providerCfg.config.BatchSize = 10
+
+	changes := &plan.Changes{
+		UpdateOld: []*endpoint.Endpoint{endpoint.NewEndpointWithTTL("old.example.com", endpoint.RecordTypeA, 300, "1.1.1.1")},
+		UpdateNew: []*endpoint.Endpoint{endpoint.NewEndpointWithTTL("old.example.com", endpoint.RecordTypeA, 600, "1.1.1.1")},
+	}
+	require.NoError(t, providerCfg.ApplyChanges(context.Background(), changes))
+
+	// the A record is updated by its reference, its missing PTR record is created
+	var requests []string
+	for _, req := range client.multiRequests {
+		for _, body := range req.Body {
+			requests = append(requests, body.Method+" "+body.Object)
+		}
+	}
+	assert.ElementsMatch(t, []string{"PUT " + (*client.mockInfobloxObjects)[0].(*ibclient.RecordA).Ref, "POST " + recordPtr}, requests)
+	assert.Empty(t, client.createdEndpoints)
+}
+
+func TestBatchRequestsNS(t *testing.T) {
+	client := &mockIBConnector{mockInfobloxObjects: &[]ibclient.IBObject{
+		createMockInfobloxObjectWithZone("sub.example.com", endpoint.RecordTypeNS, "ns1.gone.invalid", "example.com"),
+	}}
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "default", false, false, client)
+
+	// the name server no longer resolves, which does not matter to deletes and updates
+	requests, err := providerCfg.batchRequests(context.Background(), &infobloxChange{
+		Action:   infobloxDelete,
+		Endpoint: endpoint.NewEndpoint("sub.example.com", endpoint.RecordTypeNS, "ns1.gone.invalid"),
+	})
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	assert.Equal(t, map[string]interface{}{"name": "sub.example.com", "nameserver": "ns1.gone.invalid"}, requests[0].Data)
+	assert.Empty(t, client.getObjectRequests)
+
+	// NS records have neither TTL nor extensible attributes, the existing record is up to date
+	requests, err = providerCfg.batchRequests(context.Background(), &infobloxChange{
+		Action:   infobloxUpdate,
+		Endpoint: endpoint.NewEndpoint("sub.example.com", endpoint.RecordTypeNS, "ns1.gone.invalid"),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, requests)
+	require.Len(t, client.getObjectRequests, 1)
+	assert.Equal(t, recordNS, client.getObjectRequests[0].obj)
+}
@@ -0,0 +1,167 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// recordsCache keeps the records fetched from Infoblox per view and zone for the configured
+// time, so Records only fetches the zones which are stale or have been changed since. A nil
+// cache caches nothing.
+type recordsCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[recordsCacheKey]recordsCacheEntry
+	// generations count the invalidations of a view. Records fetched before an invalidation
+	// are not stored, they may predate the change which caused it.
+	generations map[string]uint64
+}
+
+type recordsCacheKey struct {
+	view string
+	zone string
+}
+
+type recordsCacheEntry struct {
+	endpoints []*endpoint.Endpoint
+	fetched   time.Time
+}
+
+func newRecordsCache(ttl time.Duration) *recordsCache {
+	return &recordsCache{
+		ttl:         ttl,
+		now:         time.Now,
+		entries:     map[recordsCacheKey]recordsCacheEntry{},
+		generations: map[string]uint64{},
+	}
+}
+
+// lookup returns a copy of the cached records of the zone, if they are fresh, and the generation
+// of the view to store the fetched records with otherwise
+func (c *recordsCache) lookup(view, zone string) ([]*endpoint.Endpoint, uint64, bool) {
+	if c == nil {
+		return nil, 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[recordsCacheKey{view: view, zone: zone}]
+	if !ok || c.now().Sub(entry.fetched) >= c.ttl {
+		recordsCacheMisses.WithLabelValues(view).Inc()
+		return nil, c.generations[view], false
+	}
+	recordsCacheHits.WithLabelValues(view).Inc()
+	return copyEndpoints(entry.endpoints), 0, true
+}
+
+// store caches a copy of the records of the zone, unless the view has been invalidated since
+// the lookup which returned the generation
+func (c *recordsCache) store(view, zone string, generation uint64, endpoints []*endpoint.Endpoint) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generations[view] != generation {
+		return
+	}
+	c.entries[recordsCacheKey{view: view, zone: zone}] = recordsCacheEntry{
+		endpoints: copyEndpoints(endpoints),
+		fetched:   c.now(),
+	}
+}
+
+// invalidate drops the cached records of the zones, all zones of the view if none is given
+func (c *recordsCache) invalidate(view string, zones ...string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generations[view]++
+	if len(zones) == 0 {
+		for key := range c.entries {
+			if key.view == view {
+				delete(c.entries, key)
+			}
+		}
+		return
+	}
+	for _, zone := range zones {
+		delete(c.entries, recordsCacheKey{view: view, zone: zone})
+	}
+}
+
+// merge replaces the cached records of the name in the zone with a copy of the endpoints of the
+// name. Zones which are not cached are fetched as a whole by Records anyway.
+func (c *recordsCache) merge(view, zone, name string, endpoints []*endpoint.Endpoint) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := recordsCacheKey{view: view, zone: zone}
+	entry, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	merged := make([]*endpoint.Endpoint, 0, len(entry.endpoints)+len(endpoints))
+	for _, ep := range entry.endpoints {
+		if !strings.EqualFold(ep.DNSName, name) {
+			merged = append(merged, ep)
+		}
+	}
+	for _, ep := range endpoints {
+		if strings.EqualFold(ep.DNSName, name) {
+			merged = append(merged, ep.DeepCopy())
+		}
+	}
+	entry.endpoints = merged
+	c.entries[key] = entry
+}
+
+// touch marks the cached records of the view as fetched now
+func (c *recordsCache) touch(view string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for key, entry := range c.entries {
+		if key.view == view {
+			entry.fetched = now
+			c.entries[key] = entry
+		}
+	}
+}
+
+// copyEndpoints copies the endpoints, Records marks the endpoints it returns
+func copyEndpoints(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	copies := make([]*endpoint.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		copies = append(copies, ep.DeepCopy())
+	}
+	return copies
+}
@@ -0,0 +1,153 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+)
+
+// fetchedZones returns the zones whose records have been fetched
+func (client *mockIBConnector) fetchedZones() map[string]bool {
+	zones := map[string]bool{}
+	for _, req := range client.getObjectRequests {
+		if zone := req.url.Query().Get("zone"); zone != "" {
+			zones[zone] = true
+		}
+	}
+	return zones
+}
+
+func newCacheTestProvider() (*Provider, *mockIBConnector) {
+	client := &mockIBConnector{
+		mockInfobloxZones: &[]ibclient.ZoneAuth{
+			createMockInfobloxZone("example.com"),
+			createMockInfobloxZone("other.com"),
+		},
+		mockInfobloxObjects: &[]ibclient.IBObject{
+			createMockInfobloxObjectWithZone("a.example.com", endpoint.RecordTypeA, "1.1.1.1", "example.com"),
+			createMockInfobloxObjectWithZone("a.other.com", endpoint.RecordTypeA, "2.2.2.2", "other.com"),
+		},
+	}
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com", "other.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, client)
+	providerCfg.cache = newRecordsCache(time.Minute)
+	return providerCfg, client
+}
+
+func TestInfobloxRecordsCache(t *testing.T) {
+	providerCfg, client := newCacheTestProvider()
+	now := time.Now()
+	providerCfg.cache.now = func() time.Time { return now }
+	hits := testutil.ToFloat64(recordsCacheHits.WithLabelValues(""))
+	misses := testutil.ToFloat64(recordsCacheMisses.WithLabelValues(""))
+
+	first, err := providerCfg.Records(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"example.com": true, "other.com": true}, client.fetchedZones())
+
+	// the zones are still listed, their records are served from the cache
+	client.getObjectRequests = nil
+	second, err := providerCfg.Records(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Len(t, client.getObjectRequests, 1)
+	assert.Empty(t, client.fetchedZones())
+	assert.Equal(t, hits+2, testutil.ToFloat64(recordsCacheHits.WithLabelValues("")))
+	assert.Equal(t, misses+2, testutil.ToFloat64(recordsCacheMisses.WithLabelValues("")))
+
+	// only the changed zone is fetched again
+	require.NoError(t, providerCfg.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{endpoint.NewEndpoint("b.example.com", endpoint.RecordTypeA, "1.1.1.2")},
+	}))
+	client.getObjectRequests = nil
+	_, err = providerCfg.Records(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"example.com": true}, client.fetchedZones())
+
+	// stale records are fetched again
+	now = now.Add(time.Minute)
+	client.getObjectRequests = nil
+	_, err = providerCfg.Records(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"example.com": true, "other.com": true}, client.fetchedZones())
+}
+
+func TestInfobloxRecordsCacheCopies(t *testing.T) {
+	providerCfg, _ := newCacheTestProvider()
+
+	first, err := providerCfg.Records(context.Background())
+	require.NoError(t, err)
+	first[0].WithProviderSpecific(providerSpecificInfobloxPtrRecord, "true")
+
+	second, err := providerCfg.Records(context.Background())
+	require.NoError(t, err)
+	_, ok := second[0].GetProviderSpecificProperty(providerSpecificInfobloxPtrRecord)
+	assert.False(t, ok)
+}
+
+func TestInfobloxRefresh(t *testing.T) {
+	providerCfg, client := newCacheTestProvider()
+
+	_, err := providerCfg.Records(context.Background())
+	require.NoError(t, err)
+
+	client.getObjectRequests = nil
+	require.NoError(t, providerCfg.Refresh(context.Background()))
+	assert.Equal(t, map[string]bool{"example.com": true, "other.com": true}, client.fetchedZones())
+
+	// the refreshed records are cached
+	client.getObjectRequests = nil
+	_, err = providerCfg.Records(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, client.fetchedZones())
+}
+
+func TestRecordsCacheInvalidatedWhileFetching(t *testing.T) {
+	cache := newRecordsCache(time.Minute)
+	_, generation, ok := cache.lookup("default", "example.com")
+	require.False(t, ok)
+
+	// the records fetched before the invalidation may predate the change
+	cache.invalidate("default", "example.com")
+	cache.store("default", "example.com", generation, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "1.1.1.1"),
+	})
+	_, _, ok = cache.lookup("default", "example.com")
+	assert.False(t, ok)
+}
+
+func TestRecordsCacheDisabled(t *testing.T) {
+	var cache *recordsCache
+	cache.store("default", "example.com", 0, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "1.1.1.1"),
+	})
+	cache.invalidate("default")
+	_, _, ok := cache.lookup("default", "example.com")
+	assert.False(t, ok)
+}
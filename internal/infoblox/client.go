@@ -0,0 +1,121 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"net/http"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+// contextConnector is an IBConnector whose WAPI requests can be bound to a context, so they are
+// aborted when the context is cancelled. The methods of IBConnector take no context.
+type contextConnector interface {
+	ibclient.IBConnector
+	WithContext(ctx context.Context) ibclient.IBConnector
+}
+
+// connectorWithContext binds the WAPI requests of the connector to the context, if the
+// connector supports it
+func connectorWithContext(ctx context.Context, connector ibclient.IBConnector) ibclient.IBConnector {
+	if c, ok := connector.(contextConnector); ok {
+		return c.WithContext(ctx)
+	}
+	return connector
+}
+
+// clientWithContext returns the client of the provider bound to the context
+func (p *Provider) clientWithContext(ctx context.Context) ibclient.IBConnector {
+	return connectorWithContext(ctx, p.client)
+}
+
+// wapiClient is the connector of the Infoblox client with multi-requests and context support.
+// The connector keeps its request builder and requestor private, so they are kept here to
+// build connectors bound to a context, which share the HTTP client and its connection pool.
+type wapiClient struct {
+	*ibclient.Connector
+	hostCfg        ibclient.HostConfig
+	authCfg        ibclient.AuthConfig
+	transportCfg   ibclient.TransportConfig
+	requestBuilder ibclient.HttpRequestBuilder
+	requestor      ibclient.HttpRequestor
+}
+
+func newWAPIClient(hostCfg ibclient.HostConfig, authCfg ibclient.AuthConfig, transportCfg ibclient.TransportConfig,
+	requestBuilder ibclient.HttpRequestBuilder, requestor ibclient.HttpRequestor) (*wapiClient, error) {
+	connector, err := ibclient.NewConnector(hostCfg, authCfg, transportCfg, requestBuilder, requestor)
+	if err != nil {
+		return nil, err
+	}
+	return &wapiClient{
+		Connector:      connector,
+		hostCfg:        hostCfg,
+		authCfg:        authCfg,
+		transportCfg:   transportCfg,
+		requestBuilder: requestBuilder,
+		requestor:      requestor,
+	}, nil
+}
+
+func (c *wapiClient) SendMultiRequest(req *ibclient.MultiRequest) ([]map[string]interface{}, error) {
+	objMgr := ibclient.NewObjectManager(c.Connector, "", "").(*ibclient.ObjectManager)
+	return objMgr.CreateMultiObject(req)
+}
+
+func (c *wapiClient) WithContext(ctx context.Context) ibclient.IBConnector {
+	connector, err := ibclient.NewConnector(c.hostCfg, c.authCfg, c.transportCfg,
+		&contextRequestBuilder{HttpRequestBuilder: c.requestBuilder, ctx: ctx},
+		initializedRequestor{HttpRequestor: c.requestor})
+	if err != nil {
+		// never happens, the configuration has been validated by the shared connector
+		log.Warnf("could not bind the Infoblox client to the context: %s", err)
+		return c
+	}
+	bound := *c
+	bound.Connector = connector
+	return &bound
+}
+
+// contextRequestBuilder binds the requests of the wrapped builder to a context
+type contextRequestBuilder struct {
+	ibclient.HttpRequestBuilder
+	ctx context.Context
+}
+
+// Init does nothing, the wrapped builder has been initialized by the shared connector
+func (b *contextRequestBuilder) Init(ibclient.HostConfig, ibclient.AuthConfig) {}
+
+func (b *contextRequestBuilder) BuildRequest(t ibclient.RequestType, obj ibclient.IBObject, ref string, queryParams *ibclient.QueryParams) (*http.Request, error) {
+	req, err := b.HttpRequestBuilder.BuildRequest(t, obj, ref, queryParams)
+	if err != nil {
+		return nil, err
+	}
+	return req.WithContext(b.ctx), nil
+}
+
+// initializedRequestor shares the HTTP client of the wrapped requestor. Initializing the
+// requestor again would replace the HTTP client and its connection pool.
+type initializedRequestor struct {
+	ibclient.HttpRequestor
+}
+
+// Init does nothing, the wrapped requestor has been initialized by the shared connector
+func (initializedRequestor) Init(ibclient.AuthConfig, ibclient.TransportConfig) {}
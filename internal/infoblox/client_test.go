@@ -0,0 +1,140 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AbsaOSS/external-dns-infoblox-webhook/pkg/webhook"
+	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/provider"
+)
+
+const webhookMediaType = "application/external.dns.webhook+json;version=1"
+
+// newCancellationTestProvider returns a provider connected to a WAPI server which answers no
+// request until the client gives up on it. The first channel receives the requests, the second
+// one is closed when the server has seen a request aborted by the client.
+func newCancellationTestProvider(t *testing.T) (*Provider, <-chan struct{}, <-chan struct{}) {
+	received := make(chan struct{}, 1)
+	aborted := make(chan struct{})
+	var once sync.Once
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case received <- struct{}{}:
+		default:
+		}
+		<-r.Context().Done()
+		once.Do(func() { close(aborted) })
+	}))
+	t.Cleanup(server.Close)
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(serverURL.Port())
+	require.NoError(t, err)
+	providerCfg, err := NewInfobloxProvider(&StartupConfig{
+		Host:                   serverURL.Hostname(),
+		Port:                   port,
+		Version:                "2.3.1",
+		View:                   "default",
+		RecordMode:             recordModeRecord,
+		FetchConcurrency:       1,
+		ReadinessCheckInterval: time.Minute,
+	}, endpoint.NewDomainFilter([]string{"example.com"}))
+	require.NoError(t, err)
+	return providerCfg, received, aborted
+}
+
+func assertAborted(t *testing.T, aborted <-chan struct{}) {
+	select {
+	case <-aborted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the WAPI request has not been aborted")
+	}
+}
+
+func TestWebhookRecordsCancelled(t *testing.T) {
+	providerCfg, received, aborted := newCancellationTestProvider(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-received
+		cancel()
+	}()
+
+	req := httptest.NewRequest(http.MethodGet, "/records", nil).WithContext(ctx)
+	req.Header.Set("Accept", webhookMediaType)
+	rec := httptest.NewRecorder()
+	webhook.New(providerCfg).Records(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assertAborted(t, aborted)
+}
+
+func TestWebhookApplyChangesCancelled(t *testing.T) {
+	providerCfg, received, aborted := newCancellationTestProvider(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-received
+		cancel()
+	}()
+
+	body := `{"Create":[{"dnsName":"a.example.com","targets":["1.2.3.4"],"recordType":"A"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/records", strings.NewReader(body)).WithContext(ctx)
+	req.Header.Set("Content-Type", webhookMediaType)
+	rec := httptest.NewRecorder()
+	webhook.New(providerCfg).ApplyChanges(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), context.Canceled.Error())
+	assertAborted(t, aborted)
+}
+
+func TestInfobloxSubmitChangesCancelled(t *testing.T) {
+	client := &mockIBConnector{
+		mockInfobloxZones: &[]ibclient.ZoneAuth{
+			createMockInfobloxZone("example.com"),
+		},
+		mockInfobloxObjects: &[]ibclient.IBObject{},
+	}
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, client)
+	providerCfg.client = newRateLimitedConnector(client, RateLimitConfig{RequestRate: 0.001})
+	// the first request takes the only token of the limiter, the next one waits for the context
+	require.NoError(t, providerCfg.client.GetObject(ibclient.NewEmptyRecordA(), "", ibclient.NewQueryParams(false, map[string]string{"name": "a.example.com"}), &[]ibclient.RecordA{}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := providerCfg.submitChanges(ctx, []*infobloxChange{
+		{Action: infobloxCreate, Endpoint: endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "1.2.3.4")},
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, client.createdEndpoints)
+}
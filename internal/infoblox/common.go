@@ -19,7 +19,11 @@ Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
 */
 
 import (
+	"fmt"
+	"net"
 	"sort"
+	"strconv"
+	"strings"
 
 	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
 	"sigs.k8s.io/external-dns/endpoint"
@@ -52,6 +56,21 @@ func ToAResponseMap(res []ibclient.RecordA) *ResponseMap {
 	return rm
 }
 
+func ToAAAAResponseMap(res []ibclient.RecordAAAA) *ResponseMap {
+	rm := &ResponseMap{
+		Map:        make(map[string]ResponseDetails),
+		RecordType: ibclient.AaaaRecord,
+	}
+	for _, record := range res {
+		if _, ok := rm.Map[AsString(record.Name)]; !ok {
+			rm.Map[AsString(record.Name)] = ResponseDetails{{Target: AsString(record.Ipv6Addr), TTL: AsInt64(record.Ttl)}}
+			continue
+		}
+		rm.Map[AsString(record.Name)] = append(rm.Map[AsString(record.Name)], ResponseDetail{Target: AsString(record.Ipv6Addr), TTL: AsInt64(record.Ttl)})
+	}
+	return rm
+}
+
 func ToCNAMEResponseMap(res []ibclient.RecordCNAME) *ResponseMap {
 	rm := &ResponseMap{
 		Map:        make(map[string]ResponseDetails),
@@ -82,6 +101,54 @@ func ToTXTResponseMap(res []ibclient.RecordTXT) *ResponseMap {
 	return rm
 }
 
+func ToMXResponseMap(res []ibclient.RecordMX) *ResponseMap {
+	rm := &ResponseMap{
+		Map:        make(map[string]ResponseDetails),
+		RecordType: ibclient.MxRecord,
+	}
+	for _, record := range res {
+		target := formatMXTarget(AsInt64(record.Preference), AsString(record.MailExchanger))
+		if _, ok := rm.Map[AsString(record.Name)]; !ok {
+			rm.Map[AsString(record.Name)] = ResponseDetails{{Target: target, TTL: AsInt64(record.Ttl)}}
+			continue
+		}
+		rm.Map[AsString(record.Name)] = append(rm.Map[AsString(record.Name)], ResponseDetail{Target: target, TTL: AsInt64(record.Ttl)})
+	}
+	return rm
+}
+
+func ToSRVResponseMap(res []ibclient.RecordSRV) *ResponseMap {
+	rm := &ResponseMap{
+		Map:        make(map[string]ResponseDetails),
+		RecordType: ibclient.SrvRecord,
+	}
+	for _, record := range res {
+		target := srvTargetFromRecord(&record).String()
+		if _, ok := rm.Map[AsString(record.Name)]; !ok {
+			rm.Map[AsString(record.Name)] = ResponseDetails{{Target: target, TTL: AsInt64(record.Ttl)}}
+			continue
+		}
+		rm.Map[AsString(record.Name)] = append(rm.Map[AsString(record.Name)], ResponseDetail{Target: target, TTL: AsInt64(record.Ttl)})
+	}
+	return rm
+}
+
+func ToNSResponseMap(res []ibclient.RecordNS) *ResponseMap {
+	rm := &ResponseMap{
+		Map:        make(map[string]ResponseDetails),
+		RecordType: endpoint.RecordTypeNS,
+	}
+	for _, record := range res {
+		// NS records inherit the TTL of their zone
+		if _, ok := rm.Map[record.Name]; !ok {
+			rm.Map[record.Name] = ResponseDetails{{Target: AsString(record.Nameserver)}}
+			continue
+		}
+		rm.Map[record.Name] = append(rm.Map[record.Name], ResponseDetail{Target: AsString(record.Nameserver)})
+	}
+	return rm
+}
+
 func ToHostResponseMap(res []ibclient.HostRecord) *ResponseMap {
 	rm := &ResponseMap{
 		Map:        make(map[string]ResponseDetails),
@@ -92,6 +159,34 @@ func ToHostResponseMap(res []ibclient.HostRecord) *ResponseMap {
 		for _, ip := range record.Ipv4Addrs {
 			rds = append(rds, ResponseDetail{Target: AsString(ip.Ipv4Addr), TTL: AsInt64(record.Ttl)})
 		}
+		// host records carrying only ipv6addrs have no A representation
+		if len(rds) == 0 {
+			continue
+		}
+		if _, ok := rm.Map[AsString(record.Name)]; !ok {
+			rm.Map[AsString(record.Name)] = rds
+			continue
+		}
+		rm.Map[AsString(record.Name)] = append(rm.Map[AsString(record.Name)], rds...)
+	}
+	return rm
+}
+
+// ToHostAAAAResponseMap maps the ipv6addrs of Host records to AAAA records,
+// the same way ToHostResponseMap does for ipv4addrs and A records
+func ToHostAAAAResponseMap(res []ibclient.HostRecord) *ResponseMap {
+	rm := &ResponseMap{
+		Map:        make(map[string]ResponseDetails),
+		RecordType: ibclient.AaaaRecord,
+	}
+	for _, record := range res {
+		rds := ResponseDetails{}
+		for _, ip := range record.Ipv6Addrs {
+			rds = append(rds, ResponseDetail{Target: AsString(ip.Ipv6Addr), TTL: AsInt64(record.Ttl)})
+		}
+		if len(rds) == 0 {
+			continue
+		}
 		if _, ok := rm.Map[AsString(record.Name)]; !ok {
 			rm.Map[AsString(record.Name)] = rds
 			continue
@@ -108,14 +203,23 @@ func ToPTRResponseMap(res []ibclient.RecordPTR) *ResponseMap {
 	}
 	for _, record := range res {
 		if _, ok := rm.Map[AsString(record.PtrdName)]; !ok {
-			rm.Map[AsString(record.PtrdName)] = ResponseDetails{{Target: AsString(record.Ipv4Addr), TTL: AsInt64(record.Ttl)}}
+			rm.Map[AsString(record.PtrdName)] = ResponseDetails{{Target: ptrAddress(&record), TTL: AsInt64(record.Ttl)}}
 			continue
 		}
-		rm.Map[AsString(record.PtrdName)] = append(rm.Map[AsString(record.PtrdName)], ResponseDetail{Target: AsString(record.Ipv4Addr), TTL: AsInt64(record.Ttl)})
+		rm.Map[AsString(record.PtrdName)] = append(rm.Map[AsString(record.PtrdName)], ResponseDetail{Target: ptrAddress(&record), TTL: AsInt64(record.Ttl)})
 	}
 	return rm
 }
 
+// ptrAddress returns the address a PTR record points from, which is either
+// its ipv4addr or, for records in ip6.arpa zones, its ipv6addr
+func ptrAddress(record *ibclient.RecordPTR) string {
+	if AsString(record.Ipv4Addr) != "" {
+		return AsString(record.Ipv4Addr)
+	}
+	return AsString(record.Ipv6Addr)
+}
+
 func (rd ResponseDetails) ToEndpointDetail() (targets []string, ttl endpoint.TTL) {
 	for _, v := range rd {
 		targets = append(targets, v.Target)
@@ -134,3 +238,157 @@ func (rm *ResponseMap) ToEndpoints() []*endpoint.Endpoint {
 	}
 	return endpoints
 }
+
+// parseMXTarget splits the external-dns MX target "<preference> <exchanger>"
+// into the Infoblox preference and mail_exchanger fields
+func parseMXTarget(target string) (preference int64, exchanger string, err error) {
+	fields := strings.Fields(target)
+	if len(fields) != 2 {
+		return 0, "", fmt.Errorf("invalid MX target '%s': expected '<preference> <exchanger>'", target)
+	}
+	preference, err = strconv.ParseInt(fields[0], 10, 32)
+	if err != nil || preference < 0 || preference > 65535 {
+		return 0, "", fmt.Errorf("invalid MX target '%s': preference must be a number between 0 and 65535", target)
+	}
+	exchanger = strings.TrimSuffix(fields[1], ".")
+	if exchanger == "" {
+		return 0, "", fmt.Errorf("invalid MX target '%s': exchanger must not be empty", target)
+	}
+	return preference, exchanger, nil
+}
+
+// formatMXTarget is the reverse of parseMXTarget
+func formatMXTarget(preference int64, exchanger string) string {
+	return fmt.Sprintf("%d %s", preference, exchanger)
+}
+
+// srvTarget holds the fields of the external-dns SRV target
+// "<priority> <weight> <port> <target>"
+type srvTarget struct {
+	Priority uint32
+	Weight   uint32
+	Port     uint32
+	Target   string
+}
+
+func (t srvTarget) String() string {
+	return fmt.Sprintf("%d %d %d %s", t.Priority, t.Weight, t.Port, t.Target)
+}
+
+func srvTargetFromRecord(record *ibclient.RecordSRV) srvTarget {
+	return srvTarget{
+		Priority: uint32(AsInt64(record.Priority)),
+		Weight:   uint32(AsInt64(record.Weight)),
+		Port:     uint32(AsInt64(record.Port)),
+		Target:   AsString(record.Target),
+	}
+}
+
+// parseSRVTarget parses the external-dns SRV target into its fields;
+// priority, weight and port are 16 bit numbers
+func parseSRVTarget(target string) (srv srvTarget, err error) {
+	fields := strings.Fields(target)
+	if len(fields) != 4 {
+		return srv, fmt.Errorf("invalid SRV target '%s': expected '<priority> <weight> <port> <target>'", target)
+	}
+	numbers := make([]uint32, 3)
+	for i, name := range []string{"priority", "weight", "port"} {
+		n, parseErr := strconv.ParseUint(fields[i], 10, 16)
+		if parseErr != nil {
+			return srv, fmt.Errorf("invalid SRV target '%s': %s must be a number between 0 and 65535", target, name)
+		}
+		numbers[i] = uint32(n)
+	}
+	srv = srvTarget{
+		Priority: numbers[0],
+		Weight:   numbers[1],
+		Port:     numbers[2],
+		Target:   strings.TrimSuffix(fields[3], "."),
+	}
+	if srv.Target == "" {
+		return srv, fmt.Errorf("invalid SRV target '%s': target must not be empty", target)
+	}
+	return srv, nil
+}
+
+func ToCAAResponseMap(res []ibclient.RecordCaa) *ResponseMap {
+	rm := &ResponseMap{
+		Map:        make(map[string]ResponseDetails),
+		RecordType: recordTypeCAA,
+	}
+	for _, record := range res {
+		target := caaTargetFromRecord(&record).String()
+		if _, ok := rm.Map[AsString(record.Name)]; !ok {
+			rm.Map[AsString(record.Name)] = ResponseDetails{{Target: target, TTL: AsInt64(record.Ttl)}}
+			continue
+		}
+		rm.Map[AsString(record.Name)] = append(rm.Map[AsString(record.Name)], ResponseDetail{Target: target, TTL: AsInt64(record.Ttl)})
+	}
+	return rm
+}
+
+// caaTarget holds the fields of the CAA target `<flags> <tag> "<value>"`
+type caaTarget struct {
+	Flag  uint32
+	Tag   string
+	Value string
+}
+
+func (t caaTarget) String() string {
+	return fmt.Sprintf("%d %s %q", t.Flag, t.Tag, t.Value)
+}
+
+func caaTargetFromRecord(record *ibclient.RecordCaa) caaTarget {
+	return caaTarget{
+		Flag:  uint32(AsInt64(record.CaFlag)),
+		Tag:   AsString(record.CaTag),
+		Value: AsString(record.CaValue),
+	}
+}
+
+// parseCAATarget parses the CAA target into its fields; flags is an 8 bit number,
+// the tag is alphanumeric and the value is everything after the tag, optionally quoted
+func parseCAATarget(target string) (caa caaTarget, err error) {
+	fields := strings.Fields(target)
+	if len(fields) < 3 {
+		return caa, fmt.Errorf("invalid CAA target '%s': expected '<flags> <tag> <value>'", target)
+	}
+	flag, err := strconv.ParseUint(fields[0], 10, 8)
+	if err != nil {
+		return caa, fmt.Errorf("invalid CAA target '%s': flags must be a number between 0 and 255", target)
+	}
+	tag := fields[1]
+	for _, c := range tag {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return caa, fmt.Errorf("invalid CAA target '%s': tag must be alphanumeric", target)
+		}
+	}
+	value := strings.TrimSpace(target)
+	value = strings.TrimSpace(strings.TrimPrefix(value, fields[0]))
+	value = strings.TrimSpace(strings.TrimPrefix(value, tag))
+	if unquoted, unquoteErr := strconv.Unquote(value); unquoteErr == nil {
+		value = unquoted
+	}
+	return caaTarget{Flag: uint32(flag), Tag: strings.ToLower(tag), Value: value}, nil
+}
+
+// hostRecordAddresses lists the IPv4 and IPv6 addresses of a Host record
+func hostRecordAddresses(host *ibclient.HostRecord) []string {
+	addresses := make([]string, 0, len(host.Ipv4Addrs)+len(host.Ipv6Addrs))
+	for _, addr := range host.Ipv4Addrs {
+		addresses = append(addresses, AsString(addr.Ipv4Addr))
+	}
+	for _, addr := range host.Ipv6Addrs {
+		addresses = append(addresses, AsString(addr.Ipv6Addr))
+	}
+	return addresses
+}
+
+// sameIP compares two addresses, taking the different notations of IPv6 addresses into account
+func sameIP(a, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return a == b
+	}
+	return ipA.Equal(ipB)
+}
@@ -0,0 +1,119 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// endpointKey identifies an endpoint within the UpdateOld and UpdateNew changes
+type endpointKey struct {
+	DNSName       string
+	RecordType    string
+	SetIdentifier string
+}
+
+func newEndpointKey(ep *endpoint.Endpoint) endpointKey {
+	return endpointKey{DNSName: ep.DNSName, RecordType: ep.RecordType, SetIdentifier: ep.SetIdentifier}
+}
+
+// diffUpdates breaks the UpdateOld and UpdateNew pairs down to single targets, as Infoblox keeps
+// one object per target:
+//   - endpoints only present in updateOld are deleted, endpoints only present in updateNew are created
+//   - targets only present in the old endpoint are deleted, targets only present in the new one are created
+//   - targets present in both are updated if TTL or provider specific properties differ, otherwise dropped
+//
+// The returned changes keep the order of the input.
+func diffUpdates(updateOld, updateNew []*endpoint.Endpoint) *plan.Changes {
+	diff := &plan.Changes{}
+
+	oldEndpoints := make(map[endpointKey]*endpoint.Endpoint, len(updateOld))
+	for _, ep := range updateOld {
+		oldEndpoints[newEndpointKey(ep)] = ep
+	}
+	newEndpoints := make(map[endpointKey]*endpoint.Endpoint, len(updateNew))
+	for _, ep := range updateNew {
+		newEndpoints[newEndpointKey(ep)] = ep
+	}
+
+	for _, oldEp := range updateOld {
+		if _, ok := newEndpoints[newEndpointKey(oldEp)]; !ok {
+			diff.Delete = append(diff.Delete, oldEp.DeepCopy())
+		}
+	}
+
+	for _, newEp := range updateNew {
+		oldEp, ok := oldEndpoints[newEndpointKey(newEp)]
+		if !ok {
+			diff.Create = append(diff.Create, newEp.DeepCopy())
+			continue
+		}
+
+		oldTargets := targetSet(oldEp.Targets)
+		newTargets := targetSet(newEp.Targets)
+		for _, target := range oldEp.Targets {
+			if !newTargets[target] {
+				diff.Delete = append(diff.Delete, withSingleTarget(oldEp, target))
+			}
+		}
+		changed := oldEp.RecordTTL != newEp.RecordTTL || !equalProviderSpecific(oldEp.ProviderSpecific, newEp.ProviderSpecific)
+		for _, target := range newEp.Targets {
+			switch {
+			case !oldTargets[target]:
+				diff.Create = append(diff.Create, withSingleTarget(newEp, target))
+			case changed:
+				diff.UpdateOld = append(diff.UpdateOld, withSingleTarget(oldEp, target))
+				diff.UpdateNew = append(diff.UpdateNew, withSingleTarget(newEp, target))
+			}
+		}
+	}
+	return diff
+}
+
+func targetSet(targets endpoint.Targets) map[string]bool {
+	m := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		m[target] = true
+	}
+	return m
+}
+
+func withSingleTarget(ep *endpoint.Endpoint, target string) *endpoint.Endpoint {
+	clone := ep.DeepCopy()
+	clone.Targets = endpoint.Targets{target}
+	return clone
+}
+
+// equalProviderSpecific compares provider specific properties regardless of their order
+func equalProviderSpecific(a, b endpoint.ProviderSpecific) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	properties := make(map[string]string, len(a))
+	for _, property := range a {
+		properties[property.Name] = property.Value
+	}
+	for _, property := range b {
+		if value, ok := properties[property.Name]; !ok || value != property.Value {
+			return false
+		}
+	}
+	return true
+}
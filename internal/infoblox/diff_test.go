@@ -0,0 +1,238 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+func TestCountDiff(t *testing.T) {
+	a := func(ttl endpoint.TTL, targets ...string) *endpoint.Endpoint {
+		return endpoint.NewEndpointWithTTL("foo.example.com", endpoint.RecordTypeA, ttl, targets...)
+	}
+	txt := func(ttl endpoint.TTL, targets ...string) *endpoint.Endpoint {
+		return endpoint.NewEndpointWithTTL("foo.example.com", endpoint.RecordTypeTXT, ttl, targets...)
+	}
+
+	tests := []struct {
+		name     string
+		changes  *plan.Changes
+		expected *plan.Changes
+	}{
+		{
+			name:     "no changes",
+			changes:  &plan.Changes{},
+			expected: &plan.Changes{},
+		},
+		{
+			name: "creates and deletes are kept",
+			changes: &plan.Changes{
+				Create: []*endpoint.Endpoint{a(300, "1.1.1.1")},
+				Delete: []*endpoint.Endpoint{txt(300, "tag")},
+			},
+			expected: &plan.Changes{
+				Create: []*endpoint.Endpoint{a(300, "1.1.1.1")},
+				Delete: []*endpoint.Endpoint{txt(300, "tag")},
+			},
+		},
+		{
+			name: "unchanged endpoint is dropped",
+			changes: &plan.Changes{
+				UpdateOld: []*endpoint.Endpoint{a(300, "1.1.1.1", "2.2.2.2")},
+				UpdateNew: []*endpoint.Endpoint{a(300, "2.2.2.2", "1.1.1.1")},
+			},
+			expected: &plan.Changes{},
+		},
+		{
+			name: "TTL change of single target",
+			changes: &plan.Changes{
+				UpdateOld: []*endpoint.Endpoint{a(300, "1.1.1.1")},
+				UpdateNew: []*endpoint.Endpoint{a(600, "1.1.1.1")},
+			},
+			expected: &plan.Changes{
+				UpdateOld: []*endpoint.Endpoint{a(300, "1.1.1.1")},
+				UpdateNew: []*endpoint.Endpoint{a(600, "1.1.1.1")},
+			},
+		},
+		{
+			name: "TTL change of multiple targets",
+			changes: &plan.Changes{
+				UpdateOld: []*endpoint.Endpoint{a(300, "1.1.1.1", "2.2.2.2")},
+				UpdateNew: []*endpoint.Endpoint{a(600, "1.1.1.1", "2.2.2.2")},
+			},
+			expected: &plan.Changes{
+				UpdateOld: []*endpoint.Endpoint{a(300, "1.1.1.1"), a(300, "2.2.2.2")},
+				UpdateNew: []*endpoint.Endpoint{a(600, "1.1.1.1"), a(600, "2.2.2.2")},
+			},
+		},
+		{
+			name: "TTL change of TXT record",
+			changes: &plan.Changes{
+				UpdateOld: []*endpoint.Endpoint{txt(300, "tag")},
+				UpdateNew: []*endpoint.Endpoint{txt(0, "tag")},
+			},
+			expected: &plan.Changes{
+				UpdateOld: []*endpoint.Endpoint{txt(300, "tag")},
+				UpdateNew: []*endpoint.Endpoint{txt(0, "tag")},
+			},
+		},
+		{
+			name: "target added",
+			changes: &plan.Changes{
+				UpdateOld: []*endpoint.Endpoint{a(300, "1.1.1.1")},
+				UpdateNew: []*endpoint.Endpoint{a(300, "1.1.1.1", "2.2.2.2")},
+			},
+			expected: &plan.Changes{
+				Create: []*endpoint.Endpoint{a(300, "2.2.2.2")},
+			},
+		},
+		{
+			name: "target removed",
+			changes: &plan.Changes{
+				UpdateOld: []*endpoint.Endpoint{a(300, "1.1.1.1", "2.2.2.2")},
+				UpdateNew: []*endpoint.Endpoint{a(300, "2.2.2.2")},
+			},
+			expected: &plan.Changes{
+				Delete: []*endpoint.Endpoint{a(300, "1.1.1.1")},
+			},
+		},
+		{
+			name: "target replaced",
+			changes: &plan.Changes{
+				UpdateOld: []*endpoint.Endpoint{txt(300, "old")},
+				UpdateNew: []*endpoint.Endpoint{txt(300, "new")},
+			},
+			expected: &plan.Changes{
+				Create: []*endpoint.Endpoint{txt(300, "new")},
+				Delete: []*endpoint.Endpoint{txt(300, "old")},
+			},
+		},
+		{
+			name: "target added, removed and kept with TTL change",
+			changes: &plan.Changes{
+				UpdateOld: []*endpoint.Endpoint{a(300, "1.1.1.1", "2.2.2.2")},
+				UpdateNew: []*endpoint.Endpoint{a(600, "2.2.2.2", "3.3.3.3")},
+			},
+			expected: &plan.Changes{
+				Create:    []*endpoint.Endpoint{a(600, "3.3.3.3")},
+				UpdateOld: []*endpoint.Endpoint{a(300, "2.2.2.2")},
+				UpdateNew: []*endpoint.Endpoint{a(600, "2.2.2.2")},
+				Delete:    []*endpoint.Endpoint{a(300, "1.1.1.1")},
+			},
+		},
+		{
+			name: "provider specific change",
+			changes: &plan.Changes{
+				UpdateOld: []*endpoint.Endpoint{a(300, "1.1.1.1")},
+				UpdateNew: []*endpoint.Endpoint{a(300, "1.1.1.1").WithProviderSpecific(providerSpecificInfobloxPtrRecord, "true")},
+			},
+			expected: &plan.Changes{
+				UpdateOld: []*endpoint.Endpoint{a(300, "1.1.1.1")},
+				UpdateNew: []*endpoint.Endpoint{a(300, "1.1.1.1").WithProviderSpecific(providerSpecificInfobloxPtrRecord, "true")},
+			},
+		},
+		{
+			name: "provider specific properties in different order",
+			changes: &plan.Changes{
+				UpdateOld: []*endpoint.Endpoint{a(300, "1.1.1.1").WithProviderSpecific("a", "1").WithProviderSpecific("b", "2")},
+				UpdateNew: []*endpoint.Endpoint{a(300, "1.1.1.1").WithProviderSpecific("b", "2").WithProviderSpecific("a", "1")},
+			},
+			expected: &plan.Changes{},
+		},
+		{
+			name: "endpoint only in UpdateOld is deleted",
+			changes: &plan.Changes{
+				UpdateOld: []*endpoint.Endpoint{a(300, "1.1.1.1", "2.2.2.2")},
+			},
+			expected: &plan.Changes{
+				Delete: []*endpoint.Endpoint{a(300, "1.1.1.1", "2.2.2.2")},
+			},
+		},
+		{
+			name: "endpoint only in UpdateNew is created",
+			changes: &plan.Changes{
+				UpdateNew: []*endpoint.Endpoint{a(300, "1.1.1.1", "2.2.2.2")},
+			},
+			expected: &plan.Changes{
+				Create: []*endpoint.Endpoint{a(300, "1.1.1.1", "2.2.2.2")},
+			},
+		},
+		{
+			name: "same name with different record types",
+			changes: &plan.Changes{
+				UpdateOld: []*endpoint.Endpoint{a(300, "1.1.1.1"), txt(300, "tag")},
+				UpdateNew: []*endpoint.Endpoint{txt(600, "tag")},
+			},
+			expected: &plan.Changes{
+				UpdateOld: []*endpoint.Endpoint{txt(300, "tag")},
+				UpdateNew: []*endpoint.Endpoint{txt(600, "tag")},
+				Delete:    []*endpoint.Endpoint{a(300, "1.1.1.1")},
+			},
+		},
+		{
+			name: "same name and type with different set identifiers",
+			changes: &plan.Changes{
+				UpdateOld: []*endpoint.Endpoint{a(300, "1.1.1.1").WithSetIdentifier("eu"), a(300, "2.2.2.2").WithSetIdentifier("us")},
+				UpdateNew: []*endpoint.Endpoint{a(600, "1.1.1.1").WithSetIdentifier("eu"), a(300, "3.3.3.3").WithSetIdentifier("us")},
+			},
+			expected: &plan.Changes{
+				Create:    []*endpoint.Endpoint{a(300, "3.3.3.3").WithSetIdentifier("us")},
+				UpdateOld: []*endpoint.Endpoint{a(300, "1.1.1.1").WithSetIdentifier("eu")},
+				UpdateNew: []*endpoint.Endpoint{a(600, "1.1.1.1").WithSetIdentifier("eu")},
+				Delete:    []*endpoint.Endpoint{a(300, "2.2.2.2").WithSetIdentifier("us")},
+			},
+		},
+		{
+			name: "renamed endpoint is deleted and created",
+			changes: &plan.Changes{
+				UpdateOld: []*endpoint.Endpoint{a(300, "1.1.1.1")},
+				UpdateNew: []*endpoint.Endpoint{endpoint.NewEndpointWithTTL("bar.example.com", endpoint.RecordTypeA, 300, "1.1.1.1")},
+			},
+			expected: &plan.Changes{
+				Create: []*endpoint.Endpoint{endpoint.NewEndpointWithTTL("bar.example.com", endpoint.RecordTypeA, 300, "1.1.1.1")},
+				Delete: []*endpoint.Endpoint{a(300, "1.1.1.1")},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &Provider{config: &StartupConfig{}}
+			p.CountDiff(tc.changes)
+			assert.Equal(t, tc.expected, tc.changes)
+		})
+	}
+}
+
+func TestCountDiffDoesNotModifyUpdates(t *testing.T) {
+	oldEp := endpoint.NewEndpointWithTTL("foo.example.com", endpoint.RecordTypeA, 300, "1.1.1.1", "2.2.2.2")
+	newEp := endpoint.NewEndpointWithTTL("foo.example.com", endpoint.RecordTypeA, 600, "2.2.2.2", "3.3.3.3")
+	p := &Provider{config: &StartupConfig{}}
+	p.CountDiff(&plan.Changes{
+		UpdateOld: []*endpoint.Endpoint{oldEp},
+		UpdateNew: []*endpoint.Endpoint{newEp},
+	})
+	assert.Equal(t, endpoint.Targets{"1.1.1.1", "2.2.2.2"}, oldEp.Targets)
+	assert.Equal(t, endpoint.Targets{"2.2.2.2", "3.3.3.3"}, newEp.Targets)
+}
@@ -0,0 +1,186 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// wapiErrorRegEx matches the errors the Infoblox client returns for failed WAPI requests,
+// the contents hold the code and text of the WAPI error as JSON
+var wapiErrorRegEx = regexp.MustCompile(`(?s)WAPI request error: (\d+)\('[^']*'\)\nContents:\n(.*)`)
+
+// wapiError holds the details of an error reported by WAPI
+type wapiError struct {
+	StatusCode int
+	Code       string `json:"code"`
+	Text       string `json:"text"`
+}
+
+// parseWAPIError extracts the HTTP status, code and text of the WAPI error from the error
+// message, the result is empty when the error did not come from WAPI
+func parseWAPIError(err error) (result wapiError) {
+	match := wapiErrorRegEx.FindStringSubmatch(err.Error())
+	if match == nil {
+		return
+	}
+	result.StatusCode, _ = strconv.Atoi(match[1])
+	// the contents are not JSON for errors of the proxy in front of WAPI, the code and text stay empty then
+	_ = json.Unmarshal([]byte(strings.TrimSpace(match[2])), &result)
+	return
+}
+
+// wapiConflictCode is the code of the WAPI error returned for objects which already exist
+const wapiConflictCode = "Client.Ibap.Data.Conflict"
+
+// isDuplicateError reports whether WAPI refused to create an object because it already exists
+func isDuplicateError(err error) bool {
+	if err == nil {
+		return false
+	}
+	return parseWAPIError(err).Code == wapiConflictCode || strings.Contains(strings.ToLower(err.Error()), "already exists")
+}
+
+// ChangeError is the error of a single change, it names the endpoint the change was made for
+// and, if the change failed in WAPI, the code and text of the WAPI error
+type ChangeError struct {
+	Endpoint   *endpoint.Endpoint
+	Action     string
+	Zone       string
+	StatusCode int
+	WAPICode   string
+	WAPIText   string
+	Err        error
+}
+
+func newChangeError(zone string, change *infobloxChange, err error) *ChangeError {
+	wapiErr := parseWAPIError(err)
+	return &ChangeError{
+		Endpoint:   change.Endpoint,
+		Action:     change.Action,
+		Zone:       zone,
+		StatusCode: wapiErr.StatusCode,
+		WAPICode:   wapiErr.Code,
+		WAPIText:   wapiErr.Text,
+		Err:        err,
+	}
+}
+
+func (e *ChangeError) Error() string {
+	reason := e.Err.Error()
+	if e.WAPIText != "" {
+		reason = fmt.Sprintf("%s (%s)", e.WAPIText, e.WAPICode)
+	}
+	return fmt.Sprintf("could not %s %s record ['%s':'%s'] in zone '%s': %s",
+		strings.ToLower(e.Action), e.Endpoint.RecordType, e.Endpoint.DNSName, e.Endpoint.Targets, e.Zone, reason)
+}
+
+func (e *ChangeError) Unwrap() error {
+	return e.Err
+}
+
+// changeErrorReport is the JSON representation of a ChangeError
+type changeErrorReport struct {
+	DNSName    string           `json:"dnsName"`
+	RecordType string           `json:"recordType"`
+	Targets    endpoint.Targets `json:"targets"`
+	Action     string           `json:"action"`
+	Zone       string           `json:"zone"`
+	StatusCode int              `json:"statusCode,omitempty"`
+	WAPICode   string           `json:"wapiCode,omitempty"`
+	WAPIText   string           `json:"wapiText,omitempty"`
+	Error      string           `json:"error"`
+}
+
+func (e *ChangeError) report() changeErrorReport {
+	return changeErrorReport{
+		DNSName:    e.Endpoint.DNSName,
+		RecordType: e.Endpoint.RecordType,
+		Targets:    e.Endpoint.Targets,
+		Action:     e.Action,
+		Zone:       e.Zone,
+		StatusCode: e.StatusCode,
+		WAPICode:   e.WAPICode,
+		WAPIText:   e.WAPIText,
+		Error:      e.Err.Error(),
+	}
+}
+
+// MarshalJSON reports the failed change as JSON
+func (e *ChangeError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.report())
+}
+
+// ChangesError is returned in continue on error mode, it collects the errors of all
+// changes which failed while the remaining changes were applied
+type ChangesError struct {
+	Attempted int
+	Errors    []*ChangeError
+}
+
+func (e *ChangesError) Error() string {
+	errs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err.Error())
+	}
+	return fmt.Sprintf("%d of %d changes failed: %s", len(e.Errors), e.Attempted, strings.Join(errs, "; "))
+}
+
+// collect adds the error of a single change to the report and returns nil, so the remaining
+// changes are applied. Other errors, and all errors without a report, are returned.
+func (e *ChangesError) collect(err error) error {
+	changeErr, ok := err.(*ChangeError)
+	if e == nil || !ok {
+		return err
+	}
+	e.Errors = append(e.Errors, changeErr)
+	return nil
+}
+
+// Unwrap returns the errors of the failed changes, so errors.As finds each of them
+func (e *ChangesError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// MarshalJSON reports the failed changes as JSON
+func (e *ChangesError) MarshalJSON() ([]byte, error) {
+	failed := make([]changeErrorReport, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		failed = append(failed, err.report())
+	}
+	return json.Marshal(struct {
+		Message   string              `json:"message"`
+		Attempted int                 `json:"attempted"`
+		Failed    []changeErrorReport `json:"failed"`
+	}{
+		Message:   fmt.Sprintf("%d of %d changes failed", len(e.Errors), e.Attempted),
+		Attempted: e.Attempted,
+		Failed:    failed,
+	})
+}
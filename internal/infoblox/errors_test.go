@@ -0,0 +1,213 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/provider"
+)
+
+const wapiConflictError = "WAPI request error: 400('400 Bad Request')\nContents:\n" +
+	`{ "Error": "AdmConDataError: None (IBDataConflictError: IB.Data.Conflict:The record 'fail.example.com' already exists.)", ` +
+	`"code": "Client.Ibap.Data.Conflict", "text": "The record 'fail.example.com' already exists."}` + "\n"
+
+func TestParseWAPIError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected wapiError
+	}{
+		{
+			name: "WAPI error",
+			err:  errors.New(wapiConflictError),
+			expected: wapiError{
+				StatusCode: 400,
+				Code:       "Client.Ibap.Data.Conflict",
+				Text:       "The record 'fail.example.com' already exists.",
+			},
+		},
+		{
+			name:     "WAPI error without JSON contents",
+			err:      errors.New("WAPI request error: 502('502 Bad Gateway')\nContents:\n<html>Bad Gateway</html>\n"),
+			expected: wapiError{StatusCode: 502},
+		},
+		{
+			name:     "wrapped WAPI error",
+			err:      errors.New("could not fetch A record ['a.example.com':'1.2.3.4'] : " + wapiConflictError),
+			expected: wapiError{StatusCode: 400, Code: "Client.Ibap.Data.Conflict", Text: "The record 'fail.example.com' already exists."},
+		},
+		{
+			name:     "other error",
+			err:      errors.New("connection refused"),
+			expected: wapiError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseWAPIError(tt.err))
+		})
+	}
+}
+
+func TestInfobloxSubmitChangesContinueOnError(t *testing.T) {
+	client := &mockIBConnector{
+		mockInfobloxZones: &[]ibclient.ZoneAuth{
+			createMockInfobloxZone("example.com"),
+		},
+		mockInfobloxObjects: &[]ibclient.IBObject{},
+		createErrors:        map[string]error{"fail.example.com": errors.New(wapiConflictError)},
+	}
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, client)
+	providerCfg.config.ContinueOnError = true
+
+	err := providerCfg.submitChanges(context.Background(), []*infobloxChange{
+		{Action: infobloxCreate, Endpoint: endpoint.NewEndpoint("fail.example.com", endpoint.RecordTypeA, "9.9.9.9")},
+		{Action: infobloxCreate, Endpoint: endpoint.NewEndpoint("mail.example.com", endpoint.RecordTypeMX, "mx.example.com")},
+		{Action: infobloxCreate, Endpoint: endpoint.NewEndpoint("new.example.com", endpoint.RecordTypeA, "1.2.3.4")},
+	})
+
+	// the failing changes do not keep the others from being applied
+	validateEndpoints(t, client.createdEndpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("new.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+	})
+
+	var changesErr *ChangesError
+	require.True(t, errors.As(err, &changesErr))
+	assert.Equal(t, 3, changesErr.Attempted)
+	require.Len(t, changesErr.Errors, 2)
+	assert.Equal(t, "fail.example.com", changesErr.Errors[0].Endpoint.DNSName)
+	assert.Equal(t, 400, changesErr.Errors[0].StatusCode)
+	assert.Equal(t, "Client.Ibap.Data.Conflict", changesErr.Errors[0].WAPICode)
+	assert.Equal(t, "mail.example.com", changesErr.Errors[1].Endpoint.DNSName)
+	assert.Empty(t, changesErr.Errors[1].WAPICode)
+	assert.Equal(t, "2 of 3 changes failed: "+
+		"could not create A record ['fail.example.com':'9.9.9.9'] in zone 'example.com': The record 'fail.example.com' already exists. (Client.Ibap.Data.Conflict); "+
+		"could not create MX record ['mail.example.com':'mx.example.com'] in zone 'example.com': could not build record: "+
+		"invalid MX target 'mx.example.com': expected '<preference> <exchanger>'",
+		err.Error())
+
+	var changeErr *ChangeError
+	require.True(t, errors.As(err, &changeErr))
+	assert.Equal(t, "fail.example.com", changeErr.Endpoint.DNSName)
+
+	var report map[string]interface{}
+	out, err := json.Marshal(changesErr)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(out, &report))
+	assert.Equal(t, "2 of 3 changes failed", report["message"])
+	assert.Equal(t, float64(3), report["attempted"])
+	failed := report["failed"].([]interface{})
+	require.Len(t, failed, 2)
+	assert.Equal(t, map[string]interface{}{
+		"dnsName":    "fail.example.com",
+		"recordType": "A",
+		"targets":    []interface{}{"9.9.9.9"},
+		"action":     infobloxCreate,
+		"zone":       "example.com",
+		"statusCode": float64(400),
+		"wapiCode":   "Client.Ibap.Data.Conflict",
+		"wapiText":   "The record 'fail.example.com' already exists.",
+		"error":      wapiConflictError,
+	}, failed[0])
+}
+
+func TestInfobloxSubmitChangesContinueOnErrorBatch(t *testing.T) {
+	client := &mockIBConnector{
+		mockInfobloxZones: &[]ibclient.ZoneAuth{
+			createMockInfobloxZone("example.com"),
+		},
+		mockInfobloxObjects: &[]ibclient.IBObject{},
+		createErrors:        map[string]error{"fail.example.com": errors.New(wapiConflictError)},
+		multiRequestErr:     errors.New(wapiConflictError),
+	}
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, client)
+	providerCfg.config.ContinueOnError = true
+	providerCfg.config.BatchSize = 10
+
+	err := providerCfg.submitChanges(context.Background(), []*infobloxChange{
+		{Action: infobloxCreate, Endpoint: endpoint.NewEndpoint("fail.example.com", endpoint.RecordTypeA, "9.9.9.9")},
+		{Action: infobloxCreate, Endpoint: endpoint.NewEndpoint("new.example.com", endpoint.RecordTypeA, "1.2.3.4")},
+	})
+
+	validateEndpoints(t, client.createdEndpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("new.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+	})
+	var changesErr *ChangesError
+	require.True(t, errors.As(err, &changesErr))
+	require.Len(t, changesErr.Errors, 1)
+	assert.Equal(t, "fail.example.com", changesErr.Errors[0].Endpoint.DNSName)
+}
+
+func TestInfobloxSubmitChangesFailedDelete(t *testing.T) {
+	for _, batchSize := range []int{0, 10} {
+		t.Run(fmt.Sprintf("batch size %d", batchSize), func(t *testing.T) {
+			client := &mockIBConnector{
+				mockInfobloxZones: &[]ibclient.ZoneAuth{
+					createMockInfobloxZone("example.com"),
+				},
+				mockInfobloxObjects: &[]ibclient.IBObject{
+					createMockInfobloxObject("www.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+					createMockInfobloxObject("old.example.com", endpoint.RecordTypeA, "1.2.3.5"),
+				},
+				deleteErrors:    map[string]error{"www.example.com": errors.New("connection refused")},
+				multiRequestErr: errors.New("connection refused"),
+			}
+			providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, client)
+			providerCfg.config.ContinueOnError = true
+			providerCfg.config.BatchSize = batchSize
+
+			err := providerCfg.submitChanges(context.Background(), []*infobloxChange{
+				{Action: infobloxCreate, Endpoint: endpoint.NewEndpoint("www.example.com", endpoint.RecordTypeCNAME, "web.example.com")},
+				{Action: infobloxCreate, Endpoint: endpoint.NewEndpoint("old.example.com", endpoint.RecordTypeCNAME, "web.example.com")},
+				{Action: infobloxCreate, Endpoint: endpoint.NewEndpoint("new.example.com", endpoint.RecordTypeA, "1.2.3.6")},
+				{Action: infobloxDelete, Endpoint: endpoint.NewEndpoint("www.example.com", endpoint.RecordTypeA, "1.2.3.4")},
+				{Action: infobloxDelete, Endpoint: endpoint.NewEndpoint("old.example.com", endpoint.RecordTypeA, "1.2.3.5")},
+			})
+
+			// the CNAME replacing the A record which could not be deleted is not created
+			validateEndpoints(t, client.createdEndpoints, []*endpoint.Endpoint{
+				endpoint.NewEndpoint("old.example.com", endpoint.RecordTypeCNAME, "web.example.com"),
+				endpoint.NewEndpoint("new.example.com", endpoint.RecordTypeA, "1.2.3.6"),
+			})
+			validateEndpoints(t, client.deletedEndpoints, []*endpoint.Endpoint{
+				endpoint.NewEndpoint("old.example.com", endpoint.RecordTypeA, ""),
+			})
+
+			var changesErr *ChangesError
+			require.True(t, errors.As(err, &changesErr))
+			assert.Equal(t, 5, changesErr.Attempted)
+			require.Len(t, changesErr.Errors, 2)
+			assert.Equal(t, infobloxDelete, changesErr.Errors[0].Action)
+			assert.Equal(t, "www.example.com", changesErr.Errors[0].Endpoint.DNSName)
+			assert.Equal(t, infobloxCreate, changesErr.Errors[1].Action)
+			assert.Equal(t, "www.example.com", changesErr.Errors[1].Endpoint.DNSName)
+			assert.Contains(t, changesErr.Errors[1].Error(), "skipped as the delete of the conflicting A record failed")
+		})
+	}
+}
@@ -21,6 +21,7 @@ Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -28,9 +29,13 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
 
 	"sigs.k8s.io/external-dns/endpoint"
 	"sigs.k8s.io/external-dns/pkg/rfc2317"
@@ -39,16 +44,44 @@ import (
 )
 
 const (
-	// provider specific key to track if PTR record was already created or not for A records
+	// provider specific key to track if PTR record was already created or not for A and AAAA records
 	providerSpecificInfobloxPtrRecord = "infoblox-ptr-record-exists"
-	infobloxCreate                    = "CREATE"
-	infobloxDelete                    = "DELETE"
-	infobloxUpdate                    = "UPDATE"
+	// provider specific key to mark A and AAAA records which are backed by a Host record
+	providerSpecificInfobloxHostRecord = "infoblox-host-record"
+	infobloxCreate                     = "CREATE"
+	infobloxDelete                     = "DELETE"
+	infobloxUpdate                     = "UPDATE"
+	// neither external-dns nor the Infoblox client define a constant for CAA records
+	recordTypeCAA = "CAA"
+	// record modes, A and AAAA endpoints are written either as record:a/record:aaaa or as record:host
+	recordModeRecord = "record"
+	recordModeHost   = "host"
+	// delay before the first retry of a failed page fetch
+	pageRetryDelay = time.Second
 )
 
+// managePTR reports whether PTR records are maintained by the provider. In host mode
+// Infoblox derives the reverse mapping from the Host records itself.
+func (p *Provider) managePTR() bool {
+	return p.config.CreatePTR && p.config.RecordMode != recordModeHost
+}
+
+// isHostRecordEndpoint reports whether the endpoint is written as a Host record, either
+// because of the host record mode or because it was read from a Host record
+func (p *Provider) isHostRecordEndpoint(ep *endpoint.Endpoint) bool {
+	if !isPTRSourceRecordType(ep.RecordType) {
+		return false
+	}
+	if p.config.RecordMode == recordModeHost {
+		return true
+	}
+	value, ok := ep.GetProviderSpecificProperty(providerSpecificInfobloxHostRecord)
+	return ok && value == "true"
+}
+
 func isNotFoundError(err error) bool {
-	_, ok := err.(*ibclient.NotFoundError)
-	return ok
+	var notFound *ibclient.NotFoundError
+	return errors.As(err, &notFound)
 }
 
 type Provider struct {
@@ -56,26 +89,69 @@ type Provider struct {
 	client       ibclient.IBConnector
 	domainFilter endpoint.DomainFilter
 	config       *StartupConfig
+	cache        *recordsCache
+	incremental  *recordsSync
+	paging       PagingConfig
+	readiness    readiness
+	// names and record types of the Host records seen by the last Records call, see AdjustEndpoints
+	hostRecordsMu sync.RWMutex
+	hostRecords   map[hostRecordKey]bool
+}
+
+// hostRecordKey is a record type of a Host record. A name may have a Host record with IPv4
+// addresses and a separate record:aaaa, only the A endpoint of the name is a Host record then.
+type hostRecordKey struct {
+	name       string
+	recordType string
 }
 
 // StartupConfig clarifies the method signature
 type StartupConfig struct {
-	Host         string `env:"INFOBLOX_HOST,required" envDefault:"localhost"`
-	Port         int    `env:"INFOBLOX_PORT,required" envDefault:"443"`
-	Username     string `env:"INFOBLOX_WAPI_USER,required"`
-	Password     string `env:"INFOBLOX_WAPI_PASSWORD,required"`
-	Version      string `env:"INFOBLOX_VERSION,required"`
-	SSLVerify    bool   `env:"INFOBLOX_SSL_VERIFY" envDefault:"true"`
-	DryRun       bool   `env:"INFOBLOX_DRY_RUN" envDefault:"false"`
-	View         string `env:"INFOBLOX_VIEW" envDefault:"default"`
-	MaxResults   int    `env:"INFOBLOX_MAX_RESULTS" envDefault:"1500"`
-	CreatePTR    bool   `env:"INFOBLOX_CREATE_PTR" envDefault:"false"`
-	DefaultTTL   int    `env:"INFOBLOX_DEFAULT_TTL" envDefault:"300"`
-	ExtAttrsJSON string `env:"INFOBLOX_EXTENSIBLE_ATTRIBUTES_JSON" envDefault:"{}"`
-	FQDNRegEx    string
-	NameRegEx    string
+	Host                   string        `env:"INFOBLOX_HOST,required" envDefault:"localhost"`
+	Port                   int           `env:"INFOBLOX_PORT,required" envDefault:"443"`
+	Username               string        `env:"INFOBLOX_WAPI_USER,required"`
+	Password               string        `env:"INFOBLOX_WAPI_PASSWORD,required"`
+	Version                string        `env:"INFOBLOX_VERSION,required"`
+	SSLVerify              bool          `env:"INFOBLOX_SSL_VERIFY" envDefault:"true"`
+	DryRun                 bool          `env:"INFOBLOX_DRY_RUN" envDefault:"false"`
+	View                   string        `env:"INFOBLOX_VIEW" envDefault:"default"`
+	MaxResults             int           `env:"INFOBLOX_MAX_RESULTS" envDefault:"1500"`
+	CreatePTR              bool          `env:"INFOBLOX_CREATE_PTR" envDefault:"false"`
+	DefaultTTL             int           `env:"INFOBLOX_DEFAULT_TTL" envDefault:"300"`
+	ExtAttrsJSON           string        `env:"INFOBLOX_EXTENSIBLE_ATTRIBUTES_JSON" envDefault:"{}"`
+	RecordMode             string        `env:"INFOBLOX_RECORD_MODE" envDefault:"record"`
+	BatchSize              int           `env:"INFOBLOX_BATCH_SIZE" envDefault:"0"`
+	Transactional          bool          `env:"INFOBLOX_TRANSACTIONAL" envDefault:"false"`
+	ContinueOnError        bool          `env:"INFOBLOX_CONTINUE_ON_ERROR" envDefault:"false"`
+	FetchConcurrency       int           `env:"INFOBLOX_FETCH_CONCURRENCY" envDefault:"4"`
+	RequestRateLimit       float64       `env:"INFOBLOX_REQUEST_RATE_LIMIT" envDefault:"0"`
+	RequestRateBurst       int           `env:"INFOBLOX_REQUEST_RATE_BURST" envDefault:"1"`
+	ReadRateLimit          float64       `env:"INFOBLOX_READ_RATE_LIMIT" envDefault:"0"`
+	ReadRateBurst          int           `env:"INFOBLOX_READ_RATE_BURST" envDefault:"1"`
+	WriteRateLimit         float64       `env:"INFOBLOX_WRITE_RATE_LIMIT" envDefault:"0"`
+	WriteRateBurst         int           `env:"INFOBLOX_WRITE_RATE_BURST" envDefault:"1"`
+	CacheTTL               time.Duration `env:"INFOBLOX_CACHE_TTL" envDefault:"0s"`
+	IncrementalSync        bool          `env:"INFOBLOX_INCREMENTAL_SYNC" envDefault:"false"`
+	PageSize               int           `env:"INFOBLOX_PAGE_SIZE" envDefault:"0"`
+	MaxPages               int           `env:"INFOBLOX_MAX_PAGES" envDefault:"1000"`
+	PageRetries            int           `env:"INFOBLOX_PAGE_RETRIES" envDefault:"2"`
+	MaxRetries             int           `env:"INFOBLOX_MAX_RETRIES" envDefault:"3"`
+	RetryBaseDelay         time.Duration `env:"INFOBLOX_RETRY_BASE_DELAY" envDefault:"500ms"`
+	RetryMaxDelay          time.Duration `env:"INFOBLOX_RETRY_MAX_DELAY" envDefault:"10s"`
+	BreakerThreshold       int           `env:"INFOBLOX_CIRCUIT_BREAKER_THRESHOLD" envDefault:"5"`
+	BreakerTimeout         time.Duration `env:"INFOBLOX_CIRCUIT_BREAKER_TIMEOUT" envDefault:"30s"`
+	FullResyncInterval     time.Duration `env:"INFOBLOX_FULL_RESYNC_INTERVAL" envDefault:"1h"`
+	ReadinessCheckInterval time.Duration `env:"INFOBLOX_READINESS_CHECK_INTERVAL" envDefault:"30s"`
+	FQDNRegEx              string
+	NameRegEx              string
 }
 
+// nsRecordReturnFields extends the default return fields of record:ns, which lack the glue addresses
+var nsRecordReturnFields = []string{"name", "nameserver", "addresses", "view", "zone"}
+
+// caaRecordReturnFields extends the default return fields of record:caa, which are only name and view
+var caaRecordReturnFields = []string{"name", "ca_flag", "ca_tag", "ca_value", "ttl", "use_ttl", "view", "zone", "extattrs"}
+
 type infobloxRecordSet struct {
 	obj ibclient.IBObject
 	res interface{}
@@ -106,7 +182,8 @@ func (mrb *ExtendedRequestBuilder) BuildRequest(t ibclient.RequestType, obj ibcl
 	req, err = mrb.WapiRequestBuilder.BuildRequest(t, obj, ref, queryParams)
 	if req.Method == "GET" {
 		query := req.URL.Query()
-		if mrb.maxResults > 0 {
+		// the page size of a paged request is set by PagingGetObject
+		if mrb.maxResults > 0 && !query.Has("_max_results") {
 			query.Set("_max_results", strconv.Itoa(mrb.maxResults))
 		}
 		_, zoneAuthQuery := obj.(*ibclient.ZoneAuth)
@@ -114,9 +191,12 @@ func (mrb *ExtendedRequestBuilder) BuildRequest(t ibclient.RequestType, obj ibcl
 			query.Set("fqdn~", mrb.fqdnRegEx)
 		}
 
-		// if we are not doing a ZoneAuth query, support the name filter
+		// if we are not doing a ZoneAuth query, support the name filter. The Grid and the changed
+		// objects of db_objects have no name to filter by.
 		_, isPTR := obj.(*ibclient.RecordPTR)
-		if !isPTR && !zoneAuthQuery && mrb.nameRegEx != "" {
+		_, isGrid := obj.(*ibclient.Grid)
+		_, isDbObjects := obj.(*ibclient.DbObjects)
+		if !isPTR && !isGrid && !isDbObjects && !zoneAuthQuery && mrb.nameRegEx != "" {
 			query.Set("name~", mrb.nameRegEx)
 		}
 
@@ -127,6 +207,53 @@ func (mrb *ExtendedRequestBuilder) BuildRequest(t ibclient.RequestType, obj ibcl
 
 // NewInfobloxProvider creates a new Infoblox provider.
 func NewInfobloxProvider(cfg *StartupConfig, domainFilter endpoint.DomainFilter) (*Provider, error) {
+	if cfg.RecordMode != recordModeRecord && cfg.RecordMode != recordModeHost {
+		return nil, fmt.Errorf("invalid record mode '%s': expected '%s' or '%s'", cfg.RecordMode, recordModeRecord, recordModeHost)
+	}
+	if cfg.Transactional && cfg.ContinueOnError {
+		return nil, fmt.Errorf("transactional mode and continue on error mode are mutually exclusive")
+	}
+	for _, limit := range []struct {
+		name  string
+		rate  float64
+		burst int
+	}{
+		{"request", cfg.RequestRateLimit, cfg.RequestRateBurst},
+		{"read", cfg.ReadRateLimit, cfg.ReadRateBurst},
+		{"write", cfg.WriteRateLimit, cfg.WriteRateBurst},
+	} {
+		if limit.rate < 0 {
+			return nil, fmt.Errorf("invalid %s rate limit %g: expected 0 to disable the limit or a positive number of requests per second", limit.name, limit.rate)
+		}
+		if limit.rate > 0 && limit.burst < 1 {
+			return nil, fmt.Errorf("invalid %s rate burst %d: expected a positive number of requests", limit.name, limit.burst)
+		}
+	}
+	if cfg.CacheTTL < 0 {
+		return nil, fmt.Errorf("invalid cache TTL %s: expected 0 to disable the cache or a positive duration", cfg.CacheTTL)
+	}
+	if cfg.IncrementalSync && cfg.CacheTTL == 0 {
+		return nil, fmt.Errorf("incremental sync requires the records cache: expected a positive cache TTL")
+	}
+	if cfg.IncrementalSync && cfg.FullResyncInterval <= 0 {
+		return nil, fmt.Errorf("invalid full resync interval %s: expected a positive duration", cfg.FullResyncInterval)
+	}
+	if cfg.PageSize < 0 || cfg.MaxPages < 0 || cfg.PageRetries < 0 {
+		return nil, fmt.Errorf("invalid paging configuration: page size %d, maximum pages %d and page retries %d must not be negative", cfg.PageSize, cfg.MaxPages, cfg.PageRetries)
+	}
+	if cfg.MaxRetries < 0 || cfg.RetryBaseDelay < 0 || cfg.RetryMaxDelay < 0 {
+		return nil, fmt.Errorf("invalid retry configuration: maximum retries %d, base delay %s and maximum delay %s must not be negative", cfg.MaxRetries, cfg.RetryBaseDelay, cfg.RetryMaxDelay)
+	}
+	if cfg.BreakerThreshold < 0 || (cfg.BreakerThreshold > 0 && cfg.BreakerTimeout <= 0) {
+		return nil, fmt.Errorf("invalid circuit breaker configuration: expected a threshold of 0 to disable the circuit breaker or a positive threshold and timeout")
+	}
+	if cfg.ReadinessCheckInterval <= 0 {
+		return nil, fmt.Errorf("invalid readiness check interval %s: expected a positive duration", cfg.ReadinessCheckInterval)
+	}
+	if cfg.BatchSize < 0 {
+		return nil, fmt.Errorf("invalid batch size %d: expected 0 to disable batching or a positive number of changes", cfg.BatchSize)
+	}
+
 	hostCfg := ibclient.HostConfig{
 		Host:    cfg.Host,
 		Port:    strconv.Itoa(cfg.Port),
@@ -164,23 +291,65 @@ func NewInfobloxProvider(cfg *StartupConfig, domainFilter endpoint.DomainFilter)
 
 	requestor := &ibclient.WapiHttpRequestor{}
 
-	client, err := ibclient.NewConnector(hostCfg, authCfg, transportConfig, requestBuilder, requestor)
+	client, err := newWAPIClient(hostCfg, authCfg, transportConfig, requestBuilder, requestor)
 	if err != nil {
 		return nil, err
 	}
 
 	provider := &Provider{
-		client:       client,
+		client:       newInstrumentedConnector(client),
 		domainFilter: domainFilter,
 		config:       cfg,
+		paging: PagingConfig{
+			PageSize:   cfg.PageSize,
+			MaxPages:   cfg.MaxPages,
+			Retries:    cfg.PageRetries,
+			RetryDelay: pageRetryDelay,
+		},
+	}
+	if cfg.PageSize == 0 && cfg.MaxResults > 0 {
+		// WAPI returns _max_results objects per page
+		provider.paging.PageSize = cfg.MaxResults
+	}
+	rateLimit := RateLimitConfig{
+		RequestRate:  cfg.RequestRateLimit,
+		RequestBurst: cfg.RequestRateBurst,
+		ReadRate:     cfg.ReadRateLimit,
+		ReadBurst:    cfg.ReadRateBurst,
+		WriteRate:    cfg.WriteRateLimit,
+		WriteBurst:   cfg.WriteRateBurst,
+	}
+	if rateLimit.enabled() {
+		provider.client = newRateLimitedConnector(provider.client, rateLimit)
+	}
+	if cfg.MaxRetries > 0 {
+		// the retry connector retries the failed pages, retrying them once more would multiply
+		// the requests sent to a struggling Grid and the failures counted by the circuit breaker
+		provider.paging.Retries = 0
+	}
+	if cfg.MaxRetries > 0 || cfg.BreakerThreshold > 0 {
+		// every retry waits for the rate limiter
+		provider.client = newRetryConnector(provider.client, RetryConfig{
+			MaxRetries:       cfg.MaxRetries,
+			BaseDelay:        cfg.RetryBaseDelay,
+			MaxDelay:         cfg.RetryMaxDelay,
+			FailureThreshold: cfg.BreakerThreshold,
+			OpenTimeout:      cfg.BreakerTimeout,
+		})
+	}
+	if cfg.CacheTTL > 0 {
+		provider.cache = newRecordsCache(cfg.CacheTTL)
+	}
+	if cfg.IncrementalSync {
+		provider.incremental = newRecordsSync(cfg.FullResyncInterval, cfg.CacheTTL)
 	}
 
 	return provider, nil
 }
 
 // Records gets the current records.
-func (p *Provider) Records(_ context.Context) (endpoints []*endpoint.Endpoint, err error) {
-	zones, err := p.zones()
+func (p *Provider) Records(ctx context.Context) (endpoints []*endpoint.Endpoint, err error) {
+	zones, err := p.zones(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("could not fetch zones: %w", err)
 	}
@@ -189,79 +358,25 @@ func (p *Provider) Records(_ context.Context) (endpoints []*endpoint.Endpoint, e
 		return nil, err
 	}
 
-	for _, zone := range zones {
-		log.Debugf("fetch records from zone '%s'", zone.Fqdn)
-		searchParams := map[string]string{"zone": zone.Fqdn, "view": p.config.View}
-		var resA []ibclient.RecordA
-		objA := ibclient.NewEmptyRecordA()
-		objA.View = p.config.View
-		objA.Ea = extAttrs
-		objA.Zone = zone.Fqdn
-		err = PagingGetObject(p.client, objA, "", searchParams, &resA)
-		if err != nil && !isNotFoundError(err) {
-			return nil, fmt.Errorf("could not fetch A records from zone '%s': %w", zone.Fqdn, err)
-		}
-		endpointsA := ToAResponseMap(resA).ToEndpoints()
-		endpoints = append(endpoints, endpointsA...)
-
-		// Include Host records since they should be treated synonymously with A records
-		var resH []ibclient.HostRecord
-		objH := ibclient.NewEmptyHostRecord()
-		objH.View = &p.config.View
-		objH.Ea = extAttrs
-		objH.Zone = zone.Fqdn
-		err = PagingGetObject(p.client, objH, "", searchParams, &resH)
-		if err != nil && !isNotFoundError(err) {
-			return nil, fmt.Errorf("could not fetch host records from zone '%s': %w", zone.Fqdn, err)
-		}
-		endpointsHost := ToHostResponseMap(resH).ToEndpoints()
-		endpoints = append(endpoints, endpointsHost...)
-
-		var resC []ibclient.RecordCNAME
-		objC := ibclient.NewEmptyRecordCNAME()
-		objC.View = &p.config.View
-		objC.Ea = extAttrs
-		objC.Zone = zone.Fqdn
-		err = PagingGetObject(p.client, objC, "", searchParams, &resC)
-		if err != nil && !isNotFoundError(err) {
-			return nil, fmt.Errorf("could not fetch CNAME records from zone '%s': %w", zone.Fqdn, err)
-		}
-		endpointsCNAME := ToCNAMEResponseMap(resC).ToEndpoints()
-		endpoints = append(endpoints, endpointsCNAME...)
-
-		var resT []ibclient.RecordTXT
-		objT := ibclient.NewEmptyRecordTXT()
-		objT.View = &p.config.View
-		objT.Ea = extAttrs
-		objT.Zone = zone.Fqdn
-		err = PagingGetObject(p.client, objT, "", searchParams, &resT)
-		if err != nil && !isNotFoundError(err) {
-			return nil, fmt.Errorf("could not fetch TXT records from zone '%s': %w", zone.Fqdn, err)
-		}
-		endpointsTXT := ToTXTResponseMap(resT).ToEndpoints()
-		endpoints = append(endpoints, endpointsTXT...)
-
-		if p.config.CreatePTR {
-			arpaZone, err := rfc2317.CidrToInAddr(zone.Fqdn)
-			if err == nil {
-				var resP []ibclient.RecordPTR
-				objP := ibclient.NewEmptyRecordPTR()
-				objP.View = p.config.View
-				objP.Ea = extAttrs
-				objP.Zone = arpaZone
-				err = PagingGetObject(p.client, objP, "", map[string]string{"zone": arpaZone, "view": p.config.View}, &resP)
-				if err != nil && !isNotFoundError(err) {
-					return nil, fmt.Errorf("could not fetch PTR records from zone '%s': %w", zone.Fqdn, err)
-				}
-				endpointsPTR := ToPTRResponseMap(resP).ToEndpoints()
-				endpoints = append(endpoints, endpointsPTR...)
-			} else {
-				log.Debugf("Could not fetch PTR records from zone '%s': %s", zone.Fqdn, err)
+	if err = p.syncRecords(ctx, zones, extAttrs); err != nil {
+		return nil, err
+	}
+	results, err := p.zoneRecords(ctx, zones, extAttrs)
+	if err != nil {
+		return nil, err
+	}
+	observeRecords(zones, results)
+	hostRecords := map[hostRecordKey]bool{}
+	for _, result := range results {
+		for _, ep := range result {
+			if value, ok := ep.GetProviderSpecificProperty(providerSpecificInfobloxHostRecord); ok && value == "true" {
+				hostRecords[hostRecordKey{name: ep.DNSName, recordType: ep.RecordType}] = true
 			}
 		}
+		endpoints = append(endpoints, result...)
 	}
 
-	if p.config.CreatePTR {
+	if p.managePTR() {
 		// save all ptr records into map for a quick look up
 		ptrRecordsMap := make(map[string]bool)
 		for _, ptrRecord := range endpoints {
@@ -272,11 +387,12 @@ func (p *Provider) Records(_ context.Context) (endpoints []*endpoint.Endpoint, e
 		}
 
 		for i := range endpoints {
-			if endpoints[i].RecordType != endpoint.RecordTypeA {
+			if !isPTRSourceRecordType(endpoints[i].RecordType) {
 				continue
 			}
-			// if PTR record already exists for A record, then mark it as such
-			if ptrRecordsMap[endpoints[i].DNSName] {
+			// if PTR record already exists for A or AAAA record, then mark it as such. The reverse
+			// mapping of a Host record is kept by Infoblox, it is never a record:ptr of its own
+			if ptrRecordsMap[endpoints[i].DNSName] || p.isHostRecordEndpoint(endpoints[i]) {
 				found := false
 				for j := range endpoints[i].ProviderSpecific {
 					if endpoints[i].ProviderSpecific[j].Name == providerSpecificInfobloxPtrRecord {
@@ -291,26 +407,310 @@ func (p *Provider) Records(_ context.Context) (endpoints []*endpoint.Endpoint, e
 		}
 	}
 
+	p.hostRecordsMu.Lock()
+	p.hostRecords = hostRecords
+	p.hostRecordsMu.Unlock()
+	p.readiness.fetched()
+
 	log.Debugf("fetched %d records from infoblox", len(endpoints))
 	return endpoints, nil
 }
 
+// zoneRecords returns the endpoints of each zone. The records of the zones found in the records
+// cache are not fetched again, the fetched ones are added to the cache.
+func (p *Provider) zoneRecords(ctx context.Context, zones []ibclient.ZoneAuth, extAttrs ibclient.EA) ([][]*endpoint.Endpoint, error) {
+	type zoneFetches struct {
+		zone       int
+		first      int
+		count      int
+		generation uint64
+	}
+	records := make([][]*endpoint.Endpoint, len(zones))
+	var fetches []recordFetch
+	var pending []zoneFetches
+	for i, zone := range zones {
+		endpoints, generation, ok := p.cache.lookup(p.config.View, zone.Fqdn)
+		if ok {
+			log.Debugf("serving records of zone '%s' from the cache", zone.Fqdn)
+			records[i] = endpoints
+			continue
+		}
+		zoneRecordFetches := p.zoneRecordFetches(zone, extAttrs, "")
+		pending = append(pending, zoneFetches{zone: i, first: len(fetches), count: len(zoneRecordFetches), generation: generation})
+		fetches = append(fetches, zoneRecordFetches...)
+	}
+	results, err := p.fetchConcurrently(ctx, fetches)
+	if err != nil {
+		return nil, err
+	}
+	for _, z := range pending {
+		var endpoints []*endpoint.Endpoint
+		for _, result := range results[z.first : z.first+z.count] {
+			endpoints = append(endpoints, result...)
+		}
+		p.cache.store(p.config.View, zones[z.zone].Fqdn, z.generation, endpoints)
+		records[z.zone] = endpoints
+	}
+	return records, nil
+}
+
+// Refresh drops the cached records and fetches them again
+func (p *Provider) Refresh(ctx context.Context) error {
+	p.incremental.reset()
+	p.cache.invalidate(p.config.View)
+	_, err := p.Records(ctx)
+	return err
+}
+
+// Health reports a degraded state while the Grid is unavailable, i.e. the circuit breaker is open
+func (p *Provider) Health() error {
+	if reporter, ok := p.client.(healthReporter); ok {
+		return reporter.Health()
+	}
+	return nil
+}
+
+// recordFetch fetches the endpoints of a record type in a zone
+type recordFetch func(ctx context.Context) ([]*endpoint.Endpoint, error)
+
+// fetchConcurrently runs the fetches on a pool of FetchConcurrency workers. The results are
+// in the order of the fetches, so the endpoints are the same as if fetched one by one. The
+// first error, or the cancellation of the context, skips the fetches not started yet.
+func (p *Provider) fetchConcurrently(ctx context.Context, fetches []recordFetch) ([][]*endpoint.Endpoint, error) {
+	results := make([][]*endpoint.Endpoint, len(fetches))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(p.config.FetchConcurrency, 1))
+	for i, fetch := range fetches {
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			endpoints, err := fetch(ctx)
+			if err != nil {
+				return err
+			}
+			results[i] = endpoints
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// zoneRecordFetches returns the fetches of all record types in the zone, restricted to the
+// records of name if it is given
+func (p *Provider) zoneRecordFetches(zone ibclient.ZoneAuth, extAttrs ibclient.EA, name string) []recordFetch {
+	searchParams := map[string]string{"zone": zone.Fqdn, "view": p.config.View}
+	if name != "" {
+		searchParams["name"] = name
+	}
+	fetches := []recordFetch{
+		func(ctx context.Context) ([]*endpoint.Endpoint, error) {
+			log.Debugf("fetch records from zone '%s'", zone.Fqdn)
+			var resA []ibclient.RecordA
+			objA := ibclient.NewEmptyRecordA()
+			objA.View = p.config.View
+			objA.Ea = extAttrs
+			objA.Zone = zone.Fqdn
+			err := PagingGetObject(ctx, p.client, p.paging, objA, "", searchParams, &resA)
+			if err != nil && !isNotFoundError(err) {
+				return nil, fmt.Errorf("could not fetch A records from zone '%s': %w", zone.Fqdn, err)
+			}
+			return ToAResponseMap(resA).ToEndpoints(), nil
+		},
+		func(ctx context.Context) ([]*endpoint.Endpoint, error) {
+			var resAAAA []ibclient.RecordAAAA
+			objAAAA := ibclient.NewEmptyRecordAAAA()
+			objAAAA.View = p.config.View
+			objAAAA.Ea = extAttrs
+			objAAAA.Zone = zone.Fqdn
+			err := PagingGetObject(ctx, p.client, p.paging, objAAAA, "", searchParams, &resAAAA)
+			if err != nil && !isNotFoundError(err) {
+				return nil, fmt.Errorf("could not fetch AAAA records from zone '%s': %w", zone.Fqdn, err)
+			}
+			return ToAAAAResponseMap(resAAAA).ToEndpoints(), nil
+		},
+		func(ctx context.Context) ([]*endpoint.Endpoint, error) {
+			// Include Host records since they should be treated synonymously with A and AAAA records
+			var resH []ibclient.HostRecord
+			objH := ibclient.NewEmptyHostRecord()
+			objH.View = &p.config.View
+			objH.Ea = extAttrs
+			objH.Zone = zone.Fqdn
+			err := PagingGetObject(ctx, p.client, p.paging, objH, "", searchParams, &resH)
+			if err != nil && !isNotFoundError(err) {
+				return nil, fmt.Errorf("could not fetch host records from zone '%s': %w", zone.Fqdn, err)
+			}
+			endpointsHost := ToHostResponseMap(resH).ToEndpoints()
+			endpointsHost = append(endpointsHost, ToHostAAAAResponseMap(resH).ToEndpoints()...)
+			for _, ep := range endpointsHost {
+				ep.WithProviderSpecific(providerSpecificInfobloxHostRecord, "true")
+			}
+			return endpointsHost, nil
+		},
+		func(ctx context.Context) ([]*endpoint.Endpoint, error) {
+			var resC []ibclient.RecordCNAME
+			objC := ibclient.NewEmptyRecordCNAME()
+			objC.View = &p.config.View
+			objC.Ea = extAttrs
+			objC.Zone = zone.Fqdn
+			err := PagingGetObject(ctx, p.client, p.paging, objC, "", searchParams, &resC)
+			if err != nil && !isNotFoundError(err) {
+				return nil, fmt.Errorf("could not fetch CNAME records from zone '%s': %w", zone.Fqdn, err)
+			}
+			return ToCNAMEResponseMap(resC).ToEndpoints(), nil
+		},
+		func(ctx context.Context) ([]*endpoint.Endpoint, error) {
+			var resT []ibclient.RecordTXT
+			objT := ibclient.NewEmptyRecordTXT()
+			objT.View = &p.config.View
+			objT.Ea = extAttrs
+			objT.Zone = zone.Fqdn
+			err := PagingGetObject(ctx, p.client, p.paging, objT, "", searchParams, &resT)
+			if err != nil && !isNotFoundError(err) {
+				return nil, fmt.Errorf("could not fetch TXT records from zone '%s': %w", zone.Fqdn, err)
+			}
+			return ToTXTResponseMap(resT).ToEndpoints(), nil
+		},
+		func(ctx context.Context) ([]*endpoint.Endpoint, error) {
+			var resMX []ibclient.RecordMX
+			objMX := ibclient.NewEmptyRecordMX()
+			objMX.View = &p.config.View
+			objMX.Ea = extAttrs
+			objMX.Zone = zone.Fqdn
+			err := PagingGetObject(ctx, p.client, p.paging, objMX, "", searchParams, &resMX)
+			if err != nil && !isNotFoundError(err) {
+				return nil, fmt.Errorf("could not fetch MX records from zone '%s': %w", zone.Fqdn, err)
+			}
+			return ToMXResponseMap(resMX).ToEndpoints(), nil
+		},
+		func(ctx context.Context) ([]*endpoint.Endpoint, error) {
+			var resSRV []ibclient.RecordSRV
+			objSRV := ibclient.NewEmptyRecordSRV()
+			objSRV.View = p.config.View
+			objSRV.Ea = extAttrs
+			objSRV.Zone = zone.Fqdn
+			err := PagingGetObject(ctx, p.client, p.paging, objSRV, "", searchParams, &resSRV)
+			if err != nil && !isNotFoundError(err) {
+				return nil, fmt.Errorf("could not fetch SRV records from zone '%s': %w", zone.Fqdn, err)
+			}
+			return ToSRVResponseMap(resSRV).ToEndpoints(), nil
+		},
+		func(ctx context.Context) ([]*endpoint.Endpoint, error) {
+			var resNS []ibclient.RecordNS
+			objNS := newEmptyRecordNS()
+			objNS.View = p.config.View
+			objNS.Zone = zone.Fqdn
+			err := PagingGetObject(ctx, p.client, p.paging, objNS, "", searchParams, &resNS)
+			if err != nil && !isNotFoundError(err) {
+				return nil, fmt.Errorf("could not fetch NS records from zone '%s': %w", zone.Fqdn, err)
+			}
+			// NS records of the zone apex are maintained by the Grid, only delegations are of interest
+			var delegationsNS []ibclient.RecordNS
+			for _, record := range resNS {
+				if !strings.EqualFold(record.Name, zone.Fqdn) {
+					delegationsNS = append(delegationsNS, record)
+				}
+			}
+			return ToNSResponseMap(delegationsNS).ToEndpoints(), nil
+		},
+		func(ctx context.Context) ([]*endpoint.Endpoint, error) {
+			var resCAA []ibclient.RecordCaa
+			objCAA := newEmptyRecordCAA()
+			objCAA.View = &p.config.View
+			objCAA.Ea = extAttrs
+			objCAA.Zone = zone.Fqdn
+			err := PagingGetObject(ctx, p.client, p.paging, objCAA, "", searchParams, &resCAA)
+			if err != nil && !isNotFoundError(err) {
+				return nil, fmt.Errorf("could not fetch CAA records from zone '%s': %w", zone.Fqdn, err)
+			}
+			return ToCAAResponseMap(resCAA).ToEndpoints(), nil
+		},
+	}
+
+	if p.managePTR() && name == "" {
+		arpaZone, err := rfc2317.CidrToInAddr(zone.Fqdn)
+		if err == nil {
+			fetches = append(fetches, func(ctx context.Context) ([]*endpoint.Endpoint, error) {
+				var resP []ibclient.RecordPTR
+				objP := ibclient.NewEmptyRecordPTR()
+				objP.View = p.config.View
+				objP.Ea = extAttrs
+				objP.Zone = arpaZone
+				err := PagingGetObject(ctx, p.client, p.paging, objP, "", map[string]string{"zone": arpaZone, "view": p.config.View}, &resP)
+				if err != nil && !isNotFoundError(err) {
+					return nil, fmt.Errorf("could not fetch PTR records from zone '%s': %w", zone.Fqdn, err)
+				}
+				return ToPTRResponseMap(resP).ToEndpoints(), nil
+			})
+		} else {
+			log.Debugf("Could not fetch PTR records from zone '%s': %s", zone.Fqdn, err)
+		}
+	}
+	return fetches
+}
+
 func (p *Provider) AdjustEndpoints(endpoints []*endpoint.Endpoint) ([]*endpoint.Endpoint, error) {
 	// Update user specified TTL (0 == disabled)
 	for _, ep := range endpoints {
+		// Infoblox NS records have no TTL of their own, so any TTL would be reported as a change on every sync
+		if ep.RecordType == endpoint.RecordTypeNS {
+			ep.RecordTTL = 0
+			continue
+		}
 		if !ep.RecordTTL.IsConfigured() {
 			ep.RecordTTL = endpoint.TTL(p.config.DefaultTTL)
 		}
 	}
 
-	if !p.config.CreatePTR {
+	// bring MX, SRV and CAA targets into the form they are read back from Infoblox, otherwise
+	// every sync would see a difference between desired and current state. Malformed
+	// targets are rejected here, before they reach WAPI
+	for _, ep := range endpoints {
+		for i, target := range ep.Targets {
+			switch ep.RecordType {
+			case endpoint.RecordTypeMX:
+				preference, exchanger, err := parseMXTarget(target)
+				if err != nil {
+					return nil, err
+				}
+				ep.Targets[i] = formatMXTarget(preference, exchanger)
+			case endpoint.RecordTypeSRV:
+				srv, err := parseSRVTarget(target)
+				if err != nil {
+					return nil, err
+				}
+				ep.Targets[i] = srv.String()
+			case recordTypeCAA:
+				caa, err := parseCAATarget(target)
+				if err != nil {
+					return nil, err
+				}
+				ep.Targets[i] = caa.String()
+			}
+		}
+	}
+
+	// mark the records which are Host records in Infoblox the same way Records does, so
+	// that the plan does not see a difference and changes are routed to the Host records
+	p.hostRecordsMu.RLock()
+	for _, ep := range endpoints {
+		if isPTRSourceRecordType(ep.RecordType) && p.hostRecords[hostRecordKey{name: ep.DNSName, recordType: ep.RecordType}] {
+			ep.SetProviderSpecificProperty(providerSpecificInfobloxHostRecord, "true")
+		}
+	}
+	p.hostRecordsMu.RUnlock()
+
+	if !p.managePTR() {
 		return endpoints, nil
 	}
 
-	// for all A records, we want to create PTR records
+	// for all A and AAAA records, we want to create PTR records
 	// so add provider specific property to track if the record was created or not
 	for i := range endpoints {
-		if endpoints[i].RecordType == endpoint.RecordTypeA {
+		if isPTRSourceRecordType(endpoints[i].RecordType) {
 			found := false
 			for j := range endpoints[i].ProviderSpecific {
 				if endpoints[i].ProviderSpecific[j].Name == providerSpecificInfobloxPtrRecord {
@@ -352,60 +752,236 @@ func zonePointerConverter(in []ibclient.ZoneAuth) []*ibclient.ZoneAuth {
 }
 
 // submitChanges sends changes to Infoblox
-func (p *Provider) submitChanges(changes []*infobloxChange) error {
+func (p *Provider) submitChanges(ctx context.Context, changes []*infobloxChange) error {
 	// return early if there is nothing to change
 	if len(changes) == 0 {
 		return nil
 	}
 
-	zones, err := p.zones()
+	zones, err := p.zones(ctx)
 	if err != nil {
 		return fmt.Errorf("could not fetch zones: %w", err)
 	}
 
-	changesByZone := p.ChangesByZone(zonePointerConverter(zones), changes)
-	for zone, changes := range changesByZone {
-		for _, change := range changes {
-			record, err := p.buildRecord(change)
-			if err != nil {
-				return fmt.Errorf("could not build record: %w", err)
-			}
-			refId, logFields, err := getRefID(record)
-			if err != nil {
+	var j *journal
+	if p.config.Transactional {
+		j = &journal{}
+	}
+	var report *ChangesError
+	if p.config.ContinueOnError {
+		report = &ChangesError{}
+	}
+
+	schedule := scheduleChanges(p.ChangesByZone(zonePointerConverter(zones), changes))
+	if !p.config.DryRun {
+		// the zones are invalidated even when a change fails, the changes before may have been applied
+		changedZones := make([]string, 0, len(schedule))
+		for _, scheduled := range schedule {
+			changedZones = append(changedZones, scheduled.zone)
+		}
+		defer p.cache.invalidate(p.config.View, changedZones...)
+	}
+
+	for _, scheduled := range schedule {
+		if report != nil {
+			report.Attempted += len(scheduled.changes)
+		}
+		if p.batchEnabled() {
+			if err = p.submitBatches(ctx, scheduled, report); err != nil {
 				return err
 			}
-			logFields["action"] = change.Action
-			logFields["zone"] = zone
-			if p.config.DryRun {
-				log.WithFields(logFields).Info("Dry run: skipping..")
-				continue
-			}
-			log.WithFields(logFields).Info("Changing record")
-			switch change.Action {
-			case infobloxCreate:
-				_, err = p.client.CreateObject(record.obj)
-				if err != nil {
-					return err
-				}
-			case infobloxDelete:
-				_, err = p.client.DeleteObject(refId)
-				if err != nil {
-					return err
-				}
-			case infobloxUpdate:
-				_, err = p.client.UpdateObject(record.obj, refId)
-				if err != nil {
-					return err
+			continue
+		}
+		for _, change := range scheduled.changes {
+			if err = report.collect(p.submitScheduled(ctx, scheduled, change, j)); err != nil {
+				if j != nil {
+					return p.rollback(ctx, j, err)
 				}
-			default:
-				return fmt.Errorf("unknown action '%s'", change.Action)
+				return err
 			}
 		}
 	}
 
+	if report != nil && len(report.Errors) > 0 {
+		return report
+	}
+	return nil
+}
+
+// submitScheduled applies a scheduled change, unless a delete it depends on failed, and records
+// whether it failed
+func (p *Provider) submitScheduled(ctx context.Context, scheduled zoneChanges, change *infobloxChange, j *journal) error {
+	var err error
+	if conflict := scheduled.failedConflict(change); conflict != nil {
+		err = newChangeError(scheduled.zone, change, fmt.Errorf("skipped as the delete of the conflicting %s record failed", conflict.Endpoint.RecordType))
+	} else {
+		err = p.submitChange(ctx, scheduled.zone, change, j)
+	}
+	if err != nil {
+		scheduled.fail(change)
+	}
+	return err
+}
+
+// submitChange applies a single change with its own WAPI requests. The inverse of the
+// applied change is recorded in the journal, if there is one.
+func (p *Provider) submitChange(ctx context.Context, zone string, change *infobloxChange, j *journal) (err error) {
+	ctx, span := startSpan(ctx, "infoblox."+strings.ToLower(change.Action), changeAttributes(zone, change)...)
+	defer func() { endSpan(span, err) }()
+	record, err := p.buildRecord(ctx, change)
+	if err != nil {
+		return newChangeError(zone, change, fmt.Errorf("could not build record: %w", err))
+	}
+	refId, logFields, err := getRefID(record)
+	if err != nil {
+		return newChangeError(zone, change, err)
+	}
+	action := change.Action
+	if host, ok := record.obj.(*ibclient.HostRecord); ok {
+		action = hostRecordAction(change.Action, refId, host)
+	} else if action == infobloxUpdate && refId == "" {
+		// the object to update is missing in Infoblox, e.g. the PTR record of an existing A record
+		record, err = p.buildRecord(ctx, &infobloxChange{Action: infobloxCreate, Endpoint: change.Endpoint})
+		if err != nil {
+			return newChangeError(zone, change, fmt.Errorf("could not build record: %w", err))
+		}
+		action = infobloxCreate
+	}
+	logFields["action"] = action
+	logFields["zone"] = zone
+	// the record may be created rather than updated, or a Host record changed rather than deleted
+	span.SetAttributes(attribute.String("infoblox.record_action", strings.ToLower(action)))
+	if action == "" {
+		log.WithFields(logFields).Warn("Host record not found, skipping..")
+		return nil
+	}
+	if change.Action == infobloxUpdate && action == infobloxUpdate && !recordDrifted(record) {
+		log.WithFields(logFields).Debug("Record is up to date, skipping..")
+		return nil
+	}
+	if p.config.DryRun {
+		log.WithFields(logFields).Info("Dry run: skipping..")
+		return nil
+	}
+	log.WithFields(logFields).Info("Changing record")
+	client := p.clientWithContext(ctx)
+	switch action {
+	case infobloxCreate:
+		var ref string
+		if ref, err = client.CreateObject(record.obj); err == nil {
+			refId = ref
+		}
+	case infobloxDelete:
+		_, err = client.DeleteObject(refId)
+	case infobloxUpdate:
+		_, err = client.UpdateObject(record.obj, refId)
+	default:
+		err = fmt.Errorf("unknown action '%s'", action)
+	}
+	if action == infobloxCreate && isDuplicateError(err) {
+		log.WithFields(logFields).Info("Record already exists, comparing with the existing record..")
+		return p.submitDuplicate(ctx, zone, change, j, err)
+	}
+	if err != nil {
+		return newChangeError(zone, change, err)
+	}
+	if j != nil {
+		j.add(zone, change, action, record, refId, p.config.View)
+	}
+	return nil
+}
+
+// submitDuplicate handles a create which failed because the record already exists, e.g. when
+// external-dns retries a change which timed out after it had been applied. The existing record
+// is left alone if it is up to date and updated otherwise. If there is no such record, the
+// create conflicted with another record and fails with the original error.
+func (p *Provider) submitDuplicate(ctx context.Context, zone string, change *infobloxChange, j *journal, createErr error) error {
+	record, err := p.buildRecord(ctx, &infobloxChange{Action: infobloxUpdate, Endpoint: change.Endpoint})
+	if err != nil {
+		return newChangeError(zone, change, fmt.Errorf("could not build record: %w", err))
+	}
+	refId, logFields, err := getRefID(record)
+	if err != nil {
+		return newChangeError(zone, change, err)
+	}
+	if refId == "" || !sameTarget(record) {
+		return newChangeError(zone, change, createErr)
+	}
+	logFields["action"] = infobloxUpdate
+	logFields["zone"] = zone
+	if !recordDrifted(record) {
+		log.WithFields(logFields).Info("Record already exists and is up to date, skipping..")
+		return nil
+	}
+	log.WithFields(logFields).Info("Record already exists, updating..")
+	if _, err = p.clientWithContext(ctx).UpdateObject(record.obj, refId); err != nil {
+		return newChangeError(zone, change, err)
+	}
+	if j != nil {
+		j.add(zone, change, infobloxUpdate, record, refId, p.config.View)
+	}
 	return nil
 }
 
+// sameTarget reports whether the existing object points to the target of the desired one.
+// The search fields of recordSet include the target for all types but CNAME.
+func sameTarget(record *infobloxRecordSet) bool {
+	if cname, ok := record.obj.(*ibclient.RecordCNAME); ok {
+		existing := *record.res.(*[]ibclient.RecordCNAME)
+		return len(existing) > 0 && AsString(existing[0].Canonical) == AsString(cname.Canonical)
+	}
+	return true
+}
+
+// recordDrifted reports whether the TTL or the extensible attributes of the existing object
+// differ from the desired object. Extensible attributes set outside of external-dns are ignored.
+func recordDrifted(record *infobloxRecordSet) bool {
+	existing := reflect.ValueOf(record.res).Elem()
+	if existing.Len() == 0 {
+		return true
+	}
+	desiredTTL, desiredUseTTL, desiredEA, ok := recordTTLAndEA(record.obj)
+	if !ok {
+		// NS records have neither TTL nor extensible attributes
+		return false
+	}
+	existingTTL, existingUseTTL, existingEA, _ := recordTTLAndEA(existing.Index(0).Addr().Interface().(ibclient.IBObject))
+	if AsInt64(desiredTTL) != AsInt64(existingTTL) || AsBool(desiredUseTTL) != AsBool(existingUseTTL) {
+		return true
+	}
+	for name, value := range desiredEA {
+		existingValue, found := existingEA[name]
+		if !found || fmt.Sprint(value) != fmt.Sprint(existingValue) {
+			return true
+		}
+	}
+	return false
+}
+
+func recordTTLAndEA(obj ibclient.IBObject) (ttl *uint32, useTTL *bool, ea ibclient.EA, ok bool) {
+	switch record := obj.(type) {
+	case *ibclient.RecordA:
+		return record.Ttl, record.UseTtl, record.Ea, true
+	case *ibclient.RecordAAAA:
+		return record.Ttl, record.UseTtl, record.Ea, true
+	case *ibclient.RecordCNAME:
+		return record.Ttl, record.UseTtl, record.Ea, true
+	case *ibclient.RecordTXT:
+		return record.Ttl, record.UseTtl, record.Ea, true
+	case *ibclient.RecordMX:
+		return record.Ttl, record.UseTtl, record.Ea, true
+	case *ibclient.RecordSRV:
+		return record.Ttl, record.UseTtl, record.Ea, true
+	case *ibclient.RecordCaa:
+		return record.Ttl, record.UseTtl, record.Ea, true
+	case *ibclient.RecordPTR:
+		return record.Ttl, record.UseTtl, record.Ea, true
+	case *ibclient.HostRecord:
+		return record.Ttl, record.UseTtl, record.Ea, true
+	}
+	return nil, nil, nil, false
+}
+
 func getRefID(record *infobloxRecordSet) (string, log.Fields, error) {
 	t := reflect.TypeOf(record.obj).Elem().Name()
 	l := log.Fields{
@@ -420,6 +996,14 @@ func getRefID(record *infobloxRecordSet) (string, log.Fields, error) {
 			return r.Ref, l, nil
 		}
 		return "", l, nil
+	case "RecordAAAA":
+		l["record"] = AsString(record.obj.(*ibclient.RecordAAAA).Name)
+		l["ttl"] = AsInt64(record.obj.(*ibclient.RecordAAAA).Ttl)
+		l["target"] = AsString(record.obj.(*ibclient.RecordAAAA).Ipv6Addr)
+		for _, r := range *record.res.(*[]ibclient.RecordAAAA) {
+			return r.Ref, l, nil
+		}
+		return "", l, nil
 	case "RecordTXT":
 		l["record"] = AsString(record.obj.(*ibclient.RecordTXT).Name)
 		l["ttl"] = AsInt64(record.obj.(*ibclient.RecordTXT).Ttl)
@@ -436,10 +1020,50 @@ func getRefID(record *infobloxRecordSet) (string, log.Fields, error) {
 			return r.Ref, l, nil
 		}
 		return "", l, nil
+	case "RecordMX":
+		l["record"] = AsString(record.obj.(*ibclient.RecordMX).Name)
+		l["ttl"] = AsInt64(record.obj.(*ibclient.RecordMX).Ttl)
+		l["target"] = formatMXTarget(AsInt64(record.obj.(*ibclient.RecordMX).Preference), AsString(record.obj.(*ibclient.RecordMX).MailExchanger))
+		for _, r := range *record.res.(*[]ibclient.RecordMX) {
+			return r.Ref, l, nil
+		}
+		return "", l, nil
+	case "RecordSRV":
+		l["record"] = AsString(record.obj.(*ibclient.RecordSRV).Name)
+		l["ttl"] = AsInt64(record.obj.(*ibclient.RecordSRV).Ttl)
+		l["target"] = srvTargetFromRecord(record.obj.(*ibclient.RecordSRV)).String()
+		for _, r := range *record.res.(*[]ibclient.RecordSRV) {
+			return r.Ref, l, nil
+		}
+		return "", l, nil
+	case "RecordNS":
+		l["record"] = record.obj.(*ibclient.RecordNS).Name
+		l["target"] = AsString(record.obj.(*ibclient.RecordNS).Nameserver)
+		for _, r := range *record.res.(*[]ibclient.RecordNS) {
+			return r.Ref, l, nil
+		}
+		return "", l, nil
+	case "RecordCaa":
+		l["record"] = AsString(record.obj.(*ibclient.RecordCaa).Name)
+		l["ttl"] = AsInt64(record.obj.(*ibclient.RecordCaa).Ttl)
+		l["target"] = caaTargetFromRecord(record.obj.(*ibclient.RecordCaa)).String()
+		for _, r := range *record.res.(*[]ibclient.RecordCaa) {
+			return r.Ref, l, nil
+		}
+		return "", l, nil
+	case "HostRecord":
+		l["record"] = AsString(record.obj.(*ibclient.HostRecord).Name)
+		l["ttl"] = AsInt64(record.obj.(*ibclient.HostRecord).Ttl)
+		l["target"] = strings.Join(hostRecordAddresses(record.obj.(*ibclient.HostRecord)), ",")
+		for _, r := range *record.res.(*[]ibclient.HostRecord) {
+			return r.Ref, l, nil
+		}
+		return "", l, nil
 	case "RecordPTR":
 		l["record"] = AsString(record.obj.(*ibclient.RecordPTR).Name)
 		l["ttl"] = AsInt64(record.obj.(*ibclient.RecordPTR).Ttl)
 		l["target"] = AsString(record.obj.(*ibclient.RecordPTR).PtrdName)
+		l["address"] = ptrAddress(record.obj.(*ibclient.RecordPTR))
 		for _, r := range *record.res.(*[]ibclient.RecordPTR) {
 			return r.Ref, l, nil
 		}
@@ -448,108 +1072,18 @@ func getRefID(record *infobloxRecordSet) (string, log.Fields, error) {
 	return "", l, fmt.Errorf("unknown type '%s'", t)
 }
 
-// if updateNew is not part of Update Old , object should be created
-// if updateOld is not part of Update New , object should be deleted
-// if it is not there (TTL might change) , object should be updated
-// if we rename the object , object should be deleted and created
+// CountDiff replaces the UpdateOld and UpdateNew changes by create, update and delete
+// operations on single targets, see diffUpdates
 func (p *Provider) CountDiff(changes *plan.Changes) {
-
-	endpointsToMap := func(eps []*endpoint.Endpoint) map[string]*endpoint.Endpoint {
-		m := map[string]*endpoint.Endpoint{}
-		for _, v := range eps {
-			m[v.DNSName+"_"+v.RecordType] = v
-		}
-		return m
-	}
-
-	targetsToMap := func(targets endpoint.Targets) map[string]bool {
-		m := map[string]bool{}
-		for _, v := range targets {
-			m[v] = true
-		}
-		return m
-	}
-
-	cloneWithSingleTarget := func(ep *endpoint.Endpoint, target string) *endpoint.Endpoint {
-		clone := ep.DeepCopy()
-		clone.Targets = endpoint.Targets{target}
-		return clone
-	}
-
-	removeTargetFromEndpoint := func(ep *endpoint.Endpoint, target string) {
-		for i, t := range ep.Targets {
-			if t == target {
-				ep.Targets = append(ep.Targets[:i], ep.Targets[i+1:]...)
-				break
-			}
-		}
-	}
-
-	removeFromEndpointSlice := func(eps []*endpoint.Endpoint, ep *endpoint.Endpoint) []*endpoint.Endpoint {
-		for i, e := range eps {
-			if e.DNSName == ep.DNSName {
-				return append(eps[:i], eps[i+1:]...)
-			}
-		}
-		return eps
-
-	}
-
-	updateNewMap := endpointsToMap(changes.UpdateNew)
-	updateOldMap := endpointsToMap(changes.UpdateOld)
-
-	for k, oldEp := range updateOldMap {
-		newEp := updateNewMap[k]
-
-		if newEp == nil {
-			changes.Delete = append(changes.Delete, oldEp)
-			changes.UpdateOld = removeFromEndpointSlice(changes.UpdateOld, oldEp)
-			delete(updateOldMap, k)
-		}
-	}
-
-	// TODO: consider if old and new can be different. If yes, then we need to handle that case
-	for k, newEp := range updateNewMap {
-		oldEp := updateOldMap[k]
-
-		//exists in updateNew bud doesnt exists in updateOld, than create
-		if oldEp == nil {
-			changes.Create = append(changes.Create, newEp)
-			changes.UpdateNew = removeFromEndpointSlice(changes.UpdateNew, newEp)
-			continue
-		}
-
-		// oldEP is found
-		oldTargets := targetsToMap(oldEp.Targets)
-		newTargets := targetsToMap(newEp.Targets)
-
-		for target := range oldTargets {
-			if !newTargets[target] {
-				// delete
-				changes.Delete = append(changes.Delete, cloneWithSingleTarget(oldEp, target))
-				removeTargetFromEndpoint(newEp, target)
-			}
-		}
-
-		for target := range newTargets {
-			if !oldTargets[target] {
-				// create
-				changes.Create = append(changes.Create, cloneWithSingleTarget(newEp, target))
-				removeTargetFromEndpoint(newEp, target)
-			}
-		}
-
-		//for target, _ := range newTargets {
-		//	if oldTargets[target] {
-		//		// update
-		//		changes.UpdateNew = append(changes.UpdateNew, cloneWithSingleTarget(newEp, target))
-		//	}
-		//}
-	}
+	diff := diffUpdates(changes.UpdateOld, changes.UpdateNew)
+	changes.Create = append(changes.Create, diff.Create...)
+	changes.UpdateOld = diff.UpdateOld
+	changes.UpdateNew = diff.UpdateNew
+	changes.Delete = append(changes.Delete, diff.Delete...)
 }
 
 // ApplyChanges applies the given changes.
-func (p *Provider) ApplyChanges(_ context.Context, changes *plan.Changes) error {
+func (p *Provider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
 
 	p.CountDiff(changes)
 
@@ -559,11 +1093,18 @@ func (p *Provider) ApplyChanges(_ context.Context, changes *plan.Changes) error
 	combinedChanges = append(combinedChanges, newIBChanges(infobloxUpdate, changes.UpdateNew)...)
 	combinedChanges = append(combinedChanges, newIBChanges(infobloxDelete, changes.Delete)...)
 
-	return p.submitChanges(combinedChanges)
+	err := p.submitChanges(ctx, combinedChanges)
+	observeChanges(combinedChanges, err)
+	return err
 }
 
-func (p *Provider) zones() ([]ibclient.ZoneAuth, error) {
-	var res, result []ibclient.ZoneAuth
+func (p *Provider) zones(ctx context.Context) (result []ibclient.ZoneAuth, err error) {
+	ctx, span := startSpan(ctx, "infoblox.zones", attribute.String("infoblox.view", p.config.View))
+	defer func() {
+		span.SetAttributes(attribute.Int("infoblox.zones", len(result)))
+		endSpan(span, err)
+	}()
+	var res []ibclient.ZoneAuth
 	obj := ibclient.NewZoneAuth(
 		ibclient.ZoneAuth{
 			View: &p.config.View,
@@ -573,7 +1114,7 @@ func (p *Provider) zones() ([]ibclient.ZoneAuth, error) {
 	if p.config.View != "" {
 		searchFields["view"] = p.config.View
 	}
-	err := PagingGetObject(p.client, obj, "", searchFields, &res)
+	err = PagingGetObject(ctx, p.client, p.paging, obj, "", searchFields, &res)
 	if err != nil && !isNotFoundError(err) {
 		return nil, err
 	}
@@ -594,6 +1135,16 @@ func (p *Provider) zones() ([]ibclient.ZoneAuth, error) {
 	return result, nil
 }
 
+// changeAttributes are the span attributes of a change
+func changeAttributes(zone string, change *infobloxChange) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("infoblox.action", strings.ToLower(change.Action)),
+		attribute.String("infoblox.zone", zone),
+		attribute.String("dns.name", change.Endpoint.DNSName),
+		attribute.String("dns.record_type", change.Endpoint.RecordType),
+	}
+}
+
 type infobloxChange struct {
 	Action   string
 	Endpoint *endpoint.Endpoint
@@ -607,13 +1158,17 @@ func (p *Provider) ChangesByZone(zones []*ibclient.ZoneAuth, changeSets []*infob
 
 	for _, c := range changeSets {
 		zone := p.findZone(zones, c.Endpoint.DNSName)
+		if c.Endpoint.RecordType == endpoint.RecordTypeNS {
+			zone = p.findParentZone(zones, c.Endpoint.DNSName)
+		}
 		if zone == nil || zone.Fqdn == "" {
 			log.Debugf("Skipping record %s because no hosted zone matching record DNS Name was detected", c.Endpoint.DNSName)
 			continue
 		}
 		changes[zone.Fqdn] = append(changes[zone.Fqdn], c)
 
-		if p.config.CreatePTR && c.Endpoint.RecordType == endpoint.RecordTypeA {
+		// Host records carry their reverse mapping, a record:ptr would duplicate it
+		if p.managePTR() && isPTRSourceRecordType(c.Endpoint.RecordType) && !p.isHostRecordEndpoint(c.Endpoint) {
 			reverseZone := p.findReverseZone(zones, c.Endpoint.Targets[0])
 			if reverseZone == nil {
 				log.Debugf("Ignoring changes to '%s' because a suitable Infoblox DNS reverse zone was not found.", c.Endpoint.Targets)
@@ -646,6 +1201,16 @@ func (p *Provider) findZone(zones []*ibclient.ZoneAuth, name string) *ibclient.Z
 	return result
 }
 
+// findParentZone finds the zone holding the delegation of name. If name is a zone on its own,
+// the delegation NS records belong to the parent zone rather than to the delegated child.
+func (p *Provider) findParentZone(zones []*ibclient.ZoneAuth, name string) *ibclient.ZoneAuth {
+	_, parent, found := strings.Cut(name, ".")
+	if !found {
+		return nil
+	}
+	return p.findZone(zones, parent)
+}
+
 func (p *Provider) findReverseZone(zones []*ibclient.ZoneAuth, name string) *ibclient.ZoneAuth {
 	ip := net.ParseIP(name)
 	networks := map[int]*ibclient.ZoneAuth{}
@@ -657,7 +1222,8 @@ func (p *Provider) findReverseZone(zones []*ibclient.ZoneAuth, name string) *ibc
 			log.WithError(err).Debugf("fqdn %s is no cidr", zone.Fqdn)
 		} else {
 			if rZoneNet.Contains(ip) {
-				_, mask := rZoneNet.Mask.Size()
+				// the prefix length, so the most specific IPv4 or IPv6 reverse zone wins
+				mask, _ := rZoneNet.Mask.Size()
 				networks[mask] = zones[i]
 				if mask > maxMask {
 					maxMask = mask
@@ -668,7 +1234,15 @@ func (p *Provider) findReverseZone(zones []*ibclient.ZoneAuth, name string) *ibc
 	return networks[maxMask]
 }
 
-func (p *Provider) recordSet(ep *endpoint.Endpoint, getObject bool) (recordSet infobloxRecordSet, err error) {
+// isPTRSourceRecordType returns true for the record types PTR records are derived from
+func isPTRSourceRecordType(recordType string) bool {
+	return recordType == endpoint.RecordTypeA || recordType == endpoint.RecordTypeAAAA
+}
+
+func (p *Provider) recordSet(ctx context.Context, ep *endpoint.Endpoint, getObject bool) (recordSet infobloxRecordSet, err error) {
+	ctx, span := startSpan(ctx, "infoblox.record_set",
+		attribute.String("dns.name", ep.DNSName), attribute.String("dns.record_type", ep.RecordType))
+	defer func() { endSpan(span, err) }()
 	var ttl uint32
 	if ep.RecordTTL.IsConfigured() {
 		ttl = uint32(ep.RecordTTL)
@@ -677,6 +1251,7 @@ func (p *Provider) recordSet(ep *endpoint.Endpoint, getObject bool) (recordSet i
 	if err != nil {
 		return
 	}
+	client := p.clientWithContext(ctx)
 	ptrToBoolTrue := true
 	switch ep.RecordType {
 	case endpoint.RecordTypeA:
@@ -689,8 +1264,8 @@ func (p *Provider) recordSet(ep *endpoint.Endpoint, getObject bool) (recordSet i
 		obj.Ttl = &ttl
 		obj.UseTtl = &ptrToBoolTrue
 		if getObject {
-			queryParams := ibclient.NewQueryParams(false, map[string]string{"name": *obj.Name, "ipv4addr": *obj.Ipv4Addr})
-			err = p.client.GetObject(obj, "", queryParams, &res)
+			queryParams := ibclient.NewQueryParams(false, recordSearchFields(obj))
+			err = client.GetObject(obj, "", queryParams, &res)
 			if err != nil && !isNotFoundError(err) {
 				err = fmt.Errorf("could not fetch A record ['%s':'%s'] : %w", *obj.Name, *obj.Ipv4Addr, err)
 				return
@@ -704,18 +1279,46 @@ func (p *Provider) recordSet(ep *endpoint.Endpoint, getObject bool) (recordSet i
 			obj: obj,
 			res: &res,
 		}
+	case endpoint.RecordTypeAAAA:
+		var res []ibclient.RecordAAAA
+		obj := ibclient.NewEmptyRecordAAAA()
+		obj.Name = &ep.DNSName
+		obj.Ipv6Addr = &ep.Targets[0]
+		obj.Ea = extAttrs
+		obj.Ttl = &ttl
+		obj.UseTtl = &ptrToBoolTrue
+		if getObject {
+			queryParams := ibclient.NewQueryParams(false, recordSearchFields(obj))
+			err = client.GetObject(obj, "", queryParams, &res)
+			if err != nil && !isNotFoundError(err) {
+				err = fmt.Errorf("could not fetch AAAA record ['%s':'%s'] : %w", *obj.Name, *obj.Ipv6Addr, err)
+				return
+			}
+		} else {
+			// If getObject is not set (action == create), we need to set the View for Infoblox to find the parent zone
+			// If View is set for the other actions, Infoblox will complain that the view field is not allowed
+			obj.View = p.config.View
+		}
+		recordSet = infobloxRecordSet{
+			obj: obj,
+			res: &res,
+		}
 	case endpoint.RecordTypePTR:
 		var res []ibclient.RecordPTR
 		obj := ibclient.NewEmptyRecordPTR()
 		obj.PtrdName = &ep.DNSName
 		// TODO: get target index
-		obj.Ipv4Addr = &ep.Targets[0]
+		if ip := net.ParseIP(ep.Targets[0]); ip != nil && ip.To4() == nil {
+			obj.Ipv6Addr = &ep.Targets[0]
+		} else {
+			obj.Ipv4Addr = &ep.Targets[0]
+		}
 		obj.Ea = extAttrs
 		obj.Ttl = &ttl
 		obj.UseTtl = &ptrToBoolTrue
 		if getObject {
-			queryParams := ibclient.NewQueryParams(false, map[string]string{"ptrdname": *obj.PtrdName, "ipv4addr": *obj.Ipv4Addr})
-			err = p.client.GetObject(obj, "", queryParams, &res)
+			queryParams := ibclient.NewQueryParams(false, recordSearchFields(obj))
+			err = client.GetObject(obj, "", queryParams, &res)
 			if err != nil && !isNotFoundError(err) {
 				return
 			}
@@ -737,9 +1340,130 @@ func (p *Provider) recordSet(ep *endpoint.Endpoint, getObject bool) (recordSet i
 		obj.Ttl = &ttl
 		obj.UseTtl = &ptrToBoolTrue
 		if getObject {
-			queryParams := ibclient.NewQueryParams(false, map[string]string{"name": *obj.Name})
-			err = p.client.GetObject(obj, "", queryParams, &res)
+			queryParams := ibclient.NewQueryParams(false, recordSearchFields(obj))
+			err = client.GetObject(obj, "", queryParams, &res)
+			if err != nil && !isNotFoundError(err) {
+				return
+			}
+		} else {
+			// If getObject is not set (action == create), we need to set the View for Infoblox to find the parent zone
+			// If View is set for the other actions, Infoblox will complain that the view field is not allowed
+			obj.View = &p.config.View
+		}
+		recordSet = infobloxRecordSet{
+			obj: obj,
+			res: &res,
+		}
+	case endpoint.RecordTypeMX:
+		var res []ibclient.RecordMX
+		var preference int64
+		var exchanger string
+		preference, exchanger, err = parseMXTarget(ep.Targets[0])
+		if err != nil {
+			return
+		}
+		pref := uint32(preference)
+		obj := ibclient.NewEmptyRecordMX()
+		obj.Name = &ep.DNSName
+		obj.Preference = &pref
+		obj.MailExchanger = &exchanger
+		obj.Ea = extAttrs
+		obj.Ttl = &ttl
+		obj.UseTtl = &ptrToBoolTrue
+		if getObject {
+			queryParams := ibclient.NewQueryParams(false, recordSearchFields(obj))
+			err = client.GetObject(obj, "", queryParams, &res)
 			if err != nil && !isNotFoundError(err) {
+				err = fmt.Errorf("could not fetch MX record ['%s':'%s'] : %w", *obj.Name, ep.Targets[0], err)
+				return
+			}
+		} else {
+			// If getObject is not set (action == create), we need to set the View for Infoblox to find the parent zone
+			// If View is set for the other actions, Infoblox will complain that the view field is not allowed
+			obj.View = &p.config.View
+		}
+		recordSet = infobloxRecordSet{
+			obj: obj,
+			res: &res,
+		}
+	case endpoint.RecordTypeSRV:
+		var res []ibclient.RecordSRV
+		var srv srvTarget
+		srv, err = parseSRVTarget(ep.Targets[0])
+		if err != nil {
+			return
+		}
+		obj := ibclient.NewEmptyRecordSRV()
+		obj.Name = &ep.DNSName
+		obj.Priority = &srv.Priority
+		obj.Weight = &srv.Weight
+		obj.Port = &srv.Port
+		obj.Target = &srv.Target
+		obj.Ea = extAttrs
+		obj.Ttl = &ttl
+		obj.UseTtl = &ptrToBoolTrue
+		if getObject {
+			queryParams := ibclient.NewQueryParams(false, recordSearchFields(obj))
+			err = client.GetObject(obj, "", queryParams, &res)
+			if err != nil && !isNotFoundError(err) {
+				err = fmt.Errorf("could not fetch SRV record ['%s':'%s'] : %w", *obj.Name, ep.Targets[0], err)
+				return
+			}
+		} else {
+			// If getObject is not set (action == create), we need to set the View for Infoblox to find the parent zone
+			// If View is set for the other actions, Infoblox will complain that the view field is not allowed
+			obj.View = p.config.View
+		}
+		recordSet = infobloxRecordSet{
+			obj: obj,
+			res: &res,
+		}
+	case endpoint.RecordTypeNS:
+		var res []ibclient.RecordNS
+		obj := newEmptyRecordNS()
+		obj.Name = ep.DNSName
+		obj.Nameserver = &ep.Targets[0]
+		if getObject {
+			queryParams := ibclient.NewQueryParams(false, recordSearchFields(obj))
+			err = client.GetObject(obj, "", queryParams, &res)
+			if err != nil && !isNotFoundError(err) {
+				err = fmt.Errorf("could not fetch NS record ['%s':'%s'] : %w", obj.Name, *obj.Nameserver, err)
+				return
+			}
+		} else {
+			// Infoblox refuses NS records without the glue addresses of the name server
+			obj.Addresses, err = p.nameServerAddresses(ctx, *obj.Nameserver)
+			if err != nil {
+				return
+			}
+			// If getObject is not set (action == create), we need to set the View for Infoblox to find the parent zone
+			// If View is set for the other actions, Infoblox will complain that the view field is not allowed
+			obj.View = p.config.View
+		}
+		recordSet = infobloxRecordSet{
+			obj: obj,
+			res: &res,
+		}
+	case recordTypeCAA:
+		var res []ibclient.RecordCaa
+		var caa caaTarget
+		caa, err = parseCAATarget(ep.Targets[0])
+		if err != nil {
+			return
+		}
+		obj := newEmptyRecordCAA()
+		obj.Name = &ep.DNSName
+		obj.CaFlag = &caa.Flag
+		obj.CaTag = &caa.Tag
+		obj.CaValue = &caa.Value
+		obj.Ea = extAttrs
+		obj.Ttl = &ttl
+		obj.UseTtl = &ptrToBoolTrue
+		if getObject {
+			queryParams := ibclient.NewQueryParams(false, recordSearchFields(obj))
+			err = client.GetObject(obj, "", queryParams, &res)
+			if err != nil && !isNotFoundError(err) {
+				err = fmt.Errorf("could not fetch CAA record ['%s':'%s'] : %w", *obj.Name, ep.Targets[0], err)
 				return
 			}
 		} else {
@@ -766,8 +1490,8 @@ func (p *Provider) recordSet(ep *endpoint.Endpoint, getObject bool) (recordSet i
 		obj.UseTtl = &ptrToBoolTrue
 		// TODO: Zone?
 		if getObject {
-			queryParams := ibclient.NewQueryParams(false, map[string]string{"name": *obj.Name})
-			err = p.client.GetObject(obj, "", queryParams, &res)
+			queryParams := ibclient.NewQueryParams(false, recordSearchFields(obj))
+			err = client.GetObject(obj, "", queryParams, &res)
 			if err != nil && !isNotFoundError(err) {
 				return
 			}
@@ -784,8 +1508,208 @@ func (p *Provider) recordSet(ep *endpoint.Endpoint, getObject bool) (recordSet i
 	return
 }
 
-func (p *Provider) buildRecord(change *infobloxChange) (*infobloxRecordSet, error) {
-	rs, err := p.recordSet(change.Endpoint, !(change.Action == infobloxCreate))
+// recordSearchFields returns the fields identifying the object of a record set in Infoblox
+func recordSearchFields(obj ibclient.IBObject) map[string]string {
+	switch record := obj.(type) {
+	case *ibclient.RecordA:
+		return map[string]string{"name": AsString(record.Name), "ipv4addr": AsString(record.Ipv4Addr)}
+	case *ibclient.RecordAAAA:
+		return map[string]string{"name": AsString(record.Name), "ipv6addr": AsString(record.Ipv6Addr)}
+	case *ibclient.RecordPTR:
+		if record.Ipv6Addr != nil {
+			return map[string]string{"ptrdname": AsString(record.PtrdName), "ipv6addr": *record.Ipv6Addr}
+		}
+		return map[string]string{"ptrdname": AsString(record.PtrdName), "ipv4addr": AsString(record.Ipv4Addr)}
+	case *ibclient.RecordCNAME:
+		return map[string]string{"name": AsString(record.Name)}
+	case *ibclient.RecordMX:
+		return map[string]string{
+			"name":           AsString(record.Name),
+			"mail_exchanger": AsString(record.MailExchanger),
+			"preference":     strconv.FormatInt(AsInt64(record.Preference), 10),
+		}
+	case *ibclient.RecordSRV:
+		return map[string]string{
+			"name":     AsString(record.Name),
+			"priority": strconv.FormatInt(AsInt64(record.Priority), 10),
+			"weight":   strconv.FormatInt(AsInt64(record.Weight), 10),
+			"port":     strconv.FormatInt(AsInt64(record.Port), 10),
+			"target":   AsString(record.Target),
+		}
+	case *ibclient.RecordNS:
+		return map[string]string{"name": record.Name, "nameserver": AsString(record.Nameserver)}
+	case *ibclient.RecordCaa:
+		return map[string]string{
+			"name":     AsString(record.Name),
+			"ca_flag":  strconv.FormatInt(AsInt64(record.CaFlag), 10),
+			"ca_tag":   AsString(record.CaTag),
+			"ca_value": AsString(record.CaValue),
+		}
+	case *ibclient.RecordTXT:
+		// a name can hold several TXT records, the text selects the one of this target
+		return map[string]string{"name": AsString(record.Name), "text": AsString(record.Text)}
+	case *ibclient.HostRecord:
+		return map[string]string{"name": AsString(record.Name)}
+	}
+	return nil
+}
+
+// hostRecordSet builds the Host record carrying the A or AAAA endpoint. A Host record
+// holds all addresses of a name, so the target is added to or removed from the
+// addresses of the existing Host record, if there is one.
+func (p *Provider) hostRecordSet(ctx context.Context, ep *endpoint.Endpoint, action string) (recordSet infobloxRecordSet, err error) {
+	var ttl uint32
+	if ep.RecordTTL.IsConfigured() {
+		ttl = uint32(ep.RecordTTL)
+	}
+	extAttrs, err := deserializeEAs(p.config.ExtAttrsJSON)
+	if err != nil {
+		return
+	}
+	client := p.clientWithContext(ctx)
+	ptrToBoolTrue := true
+
+	var res []ibclient.HostRecord
+	searchFields := map[string]string{"name": ep.DNSName}
+	if p.config.View != "" {
+		searchFields["view"] = p.config.View
+	}
+	search := ibclient.NewEmptyHostRecord()
+	search.Name = &ep.DNSName
+	err = client.GetObject(search, "", ibclient.NewQueryParams(false, searchFields), &res)
+	if err != nil && !isNotFoundError(err) {
+		err = fmt.Errorf("could not fetch host record ['%s':'%s'] : %w", ep.DNSName, ep.Targets[0], err)
+		return
+	}
+
+	obj := ibclient.NewEmptyHostRecord()
+	obj.Name = &ep.DNSName
+	obj.Ea = extAttrs
+	obj.Ttl = &ttl
+	obj.UseTtl = &ptrToBoolTrue
+	obj.EnableDns = &ptrToBoolTrue
+	obj.Ipv4Addrs = []ibclient.HostRecordIpv4Addr{}
+	obj.Ipv6Addrs = []ibclient.HostRecordIpv6Addr{}
+	if len(res) > 0 {
+		// keep the other addresses of the host
+		obj.Ipv4Addrs, obj.Ipv6Addrs = writableHostAddresses(&res[0], ep.Targets[0])
+	} else {
+		// If the host does not exist yet, we need to set the View for Infoblox to find the parent zone
+		// If View is set for the other actions, Infoblox will complain that the view field is not allowed
+		obj.View = &p.config.View
+	}
+	if action != infobloxDelete {
+		if ep.RecordType == endpoint.RecordTypeAAAA {
+			obj.Ipv6Addrs = append(obj.Ipv6Addrs, ibclient.HostRecordIpv6Addr{Ipv6Addr: &ep.Targets[0]})
+		} else {
+			obj.Ipv4Addrs = append(obj.Ipv4Addrs, ibclient.HostRecordIpv4Addr{Ipv4Addr: &ep.Targets[0]})
+		}
+	}
+	recordSet = infobloxRecordSet{
+		obj: obj,
+		res: &res,
+	}
+	return
+}
+
+// writableHostAddresses returns the addresses of the host except the given one. Only the
+// writable fields of the addresses are kept, so they can be sent back to Infoblox.
+func writableHostAddresses(host *ibclient.HostRecord, except string) ([]ibclient.HostRecordIpv4Addr, []ibclient.HostRecordIpv6Addr) {
+	ipv4Addrs := []ibclient.HostRecordIpv4Addr{}
+	for _, addr := range host.Ipv4Addrs {
+		if !sameIP(AsString(addr.Ipv4Addr), except) {
+			ipv4Addrs = append(ipv4Addrs, ibclient.HostRecordIpv4Addr{Ipv4Addr: addr.Ipv4Addr, Mac: addr.Mac, EnableDhcp: addr.EnableDhcp})
+		}
+	}
+	ipv6Addrs := []ibclient.HostRecordIpv6Addr{}
+	for _, addr := range host.Ipv6Addrs {
+		if !sameIP(AsString(addr.Ipv6Addr), except) {
+			ipv6Addrs = append(ipv6Addrs, ibclient.HostRecordIpv6Addr{Ipv6Addr: addr.Ipv6Addr, Duid: addr.Duid, EnableDhcp: addr.EnableDhcp})
+		}
+	}
+	return ipv4Addrs, ipv6Addrs
+}
+
+// hostRecordAction maps the action on a single address to the action on the Host record
+// holding it. Adding an address to an existing host or removing one of several addresses
+// updates the host. An update of a missing host creates it, as for the other records, an
+// empty action means there is no host to delete the address from.
+func hostRecordAction(action string, ref string, host *ibclient.HostRecord) string {
+	switch {
+	case ref == "" && action != infobloxDelete:
+		return infobloxCreate
+	case ref == "":
+		return ""
+	case action == infobloxDelete && len(host.Ipv4Addrs)+len(host.Ipv6Addrs) == 0:
+		return infobloxDelete
+	default:
+		return infobloxUpdate
+	}
+}
+
+// nameServerAddresses collects the glue addresses of a name server. A and AAAA records
+// in the view take precedence, name servers outside Infoblox are resolved through DNS.
+func (p *Provider) nameServerAddresses(ctx context.Context, nameServer string) ([]*ibclient.ZoneNameServer, error) {
+	var addresses []*ibclient.ZoneNameServer
+	client := p.clientWithContext(ctx)
+	queryParams := ibclient.NewQueryParams(false, map[string]string{"name": nameServer, "view": p.config.View})
+
+	var resA []ibclient.RecordA
+	objA := ibclient.NewEmptyRecordA()
+	objA.Name = &nameServer
+	err := client.GetObject(objA, "", queryParams, &resA)
+	if err != nil && !isNotFoundError(err) {
+		return nil, fmt.Errorf("could not fetch A records of name server '%s': %w", nameServer, err)
+	}
+	for _, r := range resA {
+		addresses = append(addresses, &ibclient.ZoneNameServer{Address: AsString(r.Ipv4Addr)})
+	}
+
+	var resAAAA []ibclient.RecordAAAA
+	objAAAA := ibclient.NewEmptyRecordAAAA()
+	objAAAA.Name = &nameServer
+	err = client.GetObject(objAAAA, "", queryParams, &resAAAA)
+	if err != nil && !isNotFoundError(err) {
+		return nil, fmt.Errorf("could not fetch AAAA records of name server '%s': %w", nameServer, err)
+	}
+	for _, r := range resAAAA {
+		addresses = append(addresses, &ibclient.ZoneNameServer{Address: AsString(r.Ipv6Addr)})
+	}
+
+	if len(addresses) > 0 {
+		return addresses, nil
+	}
+
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, nameServer)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve addresses of name server '%s': %w", nameServer, err)
+	}
+	for _, ip := range ips {
+		addresses = append(addresses, &ibclient.ZoneNameServer{Address: ip.IP.String()})
+	}
+	return addresses, nil
+}
+
+func newEmptyRecordNS() *ibclient.RecordNS {
+	obj := &ibclient.RecordNS{}
+	obj.SetReturnFields(nsRecordReturnFields)
+	return obj
+}
+
+func newEmptyRecordCAA() *ibclient.RecordCaa {
+	obj := &ibclient.RecordCaa{}
+	obj.SetReturnFields(caaRecordReturnFields)
+	return obj
+}
+
+func (p *Provider) buildRecord(ctx context.Context, change *infobloxChange) (*infobloxRecordSet, error) {
+	var rs infobloxRecordSet
+	var err error
+	if p.isHostRecordEndpoint(change.Endpoint) {
+		rs, err = p.hostRecordSet(ctx, change.Endpoint, change.Action)
+	} else {
+		rs, err = p.recordSet(ctx, change.Endpoint, !(change.Action == infobloxCreate))
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -22,16 +22,22 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
+	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
 	"github.com/miekg/dns"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"sigs.k8s.io/external-dns/endpoint"
 	"sigs.k8s.io/external-dns/plan"
@@ -39,13 +45,26 @@ import (
 )
 
 type mockIBConnector struct {
+	// serializes GetObject, Records fetches concurrently
+	mu                  sync.Mutex
 	mockInfobloxZones   *[]ibclient.ZoneAuth
 	mockInfobloxObjects *[]ibclient.IBObject
+	createdObjects      []ibclient.IBObject
 	createdEndpoints    []*endpoint.Endpoint
 	deletedEndpoints    []*endpoint.Endpoint
 	updatedEndpoints    []*endpoint.Endpoint
 	getObjectRequests   []*getObjectRequest
+	multiRequests       []*ibclient.MultiRequest
+	multiRequestErr     error
+	createErrors        map[string]error
+	deleteErrors        map[string]error
+	rejectDuplicates    bool
+	deletedRefs         []string
+	updatedObjects      []ibclient.IBObject
 	requestBuilder      ExtendedRequestBuilder
+	// db_objects, by ascending sequence ID
+	dbObjectChanges []dbObjectChange
+	gridError       error
 }
 
 type getObjectRequest struct {
@@ -58,12 +77,24 @@ type getObjectRequest struct {
 
 const (
 	recordA     = "record:a"
+	recordAAAA  = "record:aaaa"
 	recordCname = "record:cname"
 	recordHost  = "record:host"
 	recordTxt   = "record:txt"
 	recordPtr   = "record:ptr"
+	recordMX    = "record:mx"
+	recordSRV   = "record:srv"
+	recordNS    = "record:ns"
+	recordCAA   = "record:caa"
 )
 
+// newWAPIConflictError returns the error of the Infoblox client for a record which already exists
+func newWAPIConflictError(name string) error {
+	return fmt.Errorf("WAPI request error: 400('400 Bad Request')\nContents:\n"+
+		`{ "Error": "AdmConDataError: None (IBDataConflictError: IB.Data.Conflict:The record '%[1]s' already exists.)", `+
+		`"code": "Client.Ibap.Data.Conflict", "text": "The record '%[1]s' already exists."}`+"\n", name)
+}
+
 func (req *getObjectRequest) ExpectRequestURLQueryParam(t *testing.T, name string, value string) *getObjectRequest {
 	if req.url.Query().Get(name) != value {
 		t.Errorf("Expected GetObject Request URL to contain query parameter %s=%s, Got: %v", name, value, req.url.Query())
@@ -118,6 +149,17 @@ func (client *mockIBConnector) verifyNoMoreGetObjectRequests(t *testing.T) {
 }
 
 func (client *mockIBConnector) CreateObject(obj ibclient.IBObject) (ref string, err error) {
+	if err, ok := client.createErrors[recordSearchFields(obj)["name"]]; ok {
+		return "", err
+	}
+	if client.rejectDuplicates && client.mockInfobloxObjects != nil {
+		// WAPI refuses to create a record a second time
+		for _, existing := range *client.mockInfobloxObjects {
+			if existing.ObjectType() == obj.ObjectType() && reflect.DeepEqual(recordSearchFields(existing), recordSearchFields(obj)) {
+				return "", newWAPIConflictError(recordSearchFields(obj)["name"])
+			}
+		}
+	}
 	switch obj.ObjectType() {
 	case recordA:
 		client.createdEndpoints = append(
@@ -130,6 +172,17 @@ func (client *mockIBConnector) CreateObject(obj ibclient.IBObject) (ref string,
 		)
 		ref = fmt.Sprintf("%s/%s:%s/default", obj.ObjectType(), base64.StdEncoding.EncodeToString([]byte(*obj.(*ibclient.RecordA).Name)), *obj.(*ibclient.RecordA).Name)
 		obj.(*ibclient.RecordA).Ref = ref
+	case recordAAAA:
+		client.createdEndpoints = append(
+			client.createdEndpoints,
+			endpoint.NewEndpoint(
+				*obj.(*ibclient.RecordAAAA).Name,
+				endpoint.RecordTypeAAAA,
+				*obj.(*ibclient.RecordAAAA).Ipv6Addr,
+			),
+		)
+		ref = fmt.Sprintf("%s/%s:%s/default", obj.ObjectType(), base64.StdEncoding.EncodeToString([]byte(*obj.(*ibclient.RecordAAAA).Name)), *obj.(*ibclient.RecordAAAA).Name)
+		obj.(*ibclient.RecordAAAA).Ref = ref
 	case recordCname:
 		client.createdEndpoints = append(
 			client.createdEndpoints,
@@ -152,6 +205,16 @@ func (client *mockIBConnector) CreateObject(obj ibclient.IBObject) (ref string,
 				),
 			)
 		}
+		for _, i := range obj.(*ibclient.HostRecord).Ipv6Addrs {
+			client.createdEndpoints = append(
+				client.createdEndpoints,
+				endpoint.NewEndpoint(
+					*obj.(*ibclient.HostRecord).Name,
+					endpoint.RecordTypeAAAA,
+					*i.Ipv6Addr,
+				),
+			)
+		}
 		ref = fmt.Sprintf("%s/%s:%s/default", obj.ObjectType(), base64.StdEncoding.EncodeToString([]byte(*obj.(*ibclient.HostRecord).Name)), *obj.(*ibclient.HostRecord).Name)
 		obj.(*ibclient.HostRecord).Ref = ref
 	case recordTxt:
@@ -165,22 +228,67 @@ func (client *mockIBConnector) CreateObject(obj ibclient.IBObject) (ref string,
 		)
 		obj.(*ibclient.RecordTXT).Ref = ref
 		ref = fmt.Sprintf("%s/%s:%s/default", obj.ObjectType(), base64.StdEncoding.EncodeToString([]byte(*obj.(*ibclient.RecordTXT).Name)), *obj.(*ibclient.RecordTXT).Name)
+	case recordMX:
+		client.createdEndpoints = append(
+			client.createdEndpoints,
+			endpoint.NewEndpoint(
+				*obj.(*ibclient.RecordMX).Name,
+				endpoint.RecordTypeMX,
+				formatMXTarget(AsInt64(obj.(*ibclient.RecordMX).Preference), *obj.(*ibclient.RecordMX).MailExchanger),
+			),
+		)
+		ref = fmt.Sprintf("%s/%s:%s/default", obj.ObjectType(), base64.StdEncoding.EncodeToString([]byte(*obj.(*ibclient.RecordMX).Name)), *obj.(*ibclient.RecordMX).Name)
+		obj.(*ibclient.RecordMX).Ref = ref
+	case recordSRV:
+		client.createdEndpoints = append(
+			client.createdEndpoints,
+			endpoint.NewEndpoint(
+				*obj.(*ibclient.RecordSRV).Name,
+				endpoint.RecordTypeSRV,
+				srvTargetFromRecord(obj.(*ibclient.RecordSRV)).String(),
+			),
+		)
+		ref = fmt.Sprintf("%s/%s:%s/default", obj.ObjectType(), base64.StdEncoding.EncodeToString([]byte(*obj.(*ibclient.RecordSRV).Name)), *obj.(*ibclient.RecordSRV).Name)
+		obj.(*ibclient.RecordSRV).Ref = ref
+	case recordCAA:
+		client.createdEndpoints = append(
+			client.createdEndpoints,
+			endpoint.NewEndpoint(
+				*obj.(*ibclient.RecordCaa).Name,
+				recordTypeCAA,
+				caaTargetFromRecord(obj.(*ibclient.RecordCaa)).String(),
+			),
+		)
+		ref = fmt.Sprintf("%s/%s:%s/default", obj.ObjectType(), base64.StdEncoding.EncodeToString([]byte(*obj.(*ibclient.RecordCaa).Name)), *obj.(*ibclient.RecordCaa).Name)
+		obj.(*ibclient.RecordCaa).Ref = ref
+	case recordNS:
+		client.createdEndpoints = append(
+			client.createdEndpoints,
+			endpoint.NewEndpoint(
+				obj.(*ibclient.RecordNS).Name,
+				endpoint.RecordTypeNS,
+				*obj.(*ibclient.RecordNS).Nameserver,
+			),
+		)
+		ref = fmt.Sprintf("%s/%s:%s/default", obj.ObjectType(), base64.StdEncoding.EncodeToString([]byte(obj.(*ibclient.RecordNS).Name)), obj.(*ibclient.RecordNS).Name)
+		obj.(*ibclient.RecordNS).Ref = ref
 	case recordPtr:
 		client.createdEndpoints = append(
 			client.createdEndpoints,
 			endpoint.NewEndpoint(
 				*obj.(*ibclient.RecordPTR).PtrdName,
 				endpoint.RecordTypePTR,
-				*obj.(*ibclient.RecordPTR).Ipv4Addr,
+				ptrAddress(obj.(*ibclient.RecordPTR)),
 			),
 		)
 		obj.(*ibclient.RecordPTR).Ref = ref
-		reverseAddr, err := dns.ReverseAddr(*obj.(*ibclient.RecordPTR).Ipv4Addr)
+		reverseAddr, err := dns.ReverseAddr(ptrAddress(obj.(*ibclient.RecordPTR)))
 		if err != nil {
-			return ref, fmt.Errorf("unable to create reverse addr from %s", *obj.(*ibclient.RecordPTR).Ipv4Addr)
+			return ref, fmt.Errorf("unable to create reverse addr from %s", ptrAddress(obj.(*ibclient.RecordPTR)))
 		}
 		ref = fmt.Sprintf("%s/%s:%s/default", obj.ObjectType(), base64.StdEncoding.EncodeToString([]byte(*obj.(*ibclient.RecordPTR).PtrdName)), reverseAddr)
 	}
+	client.createdObjects = append(client.createdObjects, obj)
 	*client.mockInfobloxObjects = append(
 		*client.mockInfobloxObjects,
 		obj,
@@ -190,12 +298,16 @@ func (client *mockIBConnector) CreateObject(obj ibclient.IBObject) (ref string,
 
 // nolint: gocyclo
 func (client *mockIBConnector) GetObject(obj ibclient.IBObject, ref string, queryParams *ibclient.QueryParams, res interface{}) (err error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
 	isPagingType := false
 	switch res.(type) {
 	case *pagingResponseStruct[ibclient.ZoneAuth]:
 		isPagingType = true
 	case *pagingResponseStruct[ibclient.RecordA]:
 		isPagingType = true
+	case *pagingResponseStruct[ibclient.RecordAAAA]:
+		isPagingType = true
 	case *pagingResponseStruct[ibclient.HostRecord]:
 		isPagingType = true
 	case *pagingResponseStruct[ibclient.RecordTXT]:
@@ -204,6 +316,16 @@ func (client *mockIBConnector) GetObject(obj ibclient.IBObject, ref string, quer
 		isPagingType = true
 	case *pagingResponseStruct[ibclient.RecordCNAME]:
 		isPagingType = true
+	case *pagingResponseStruct[ibclient.RecordMX]:
+		isPagingType = true
+	case *pagingResponseStruct[ibclient.RecordSRV]:
+		isPagingType = true
+	case *pagingResponseStruct[ibclient.RecordNS]:
+		isPagingType = true
+	case *pagingResponseStruct[ibclient.RecordCaa]:
+		isPagingType = true
+	case *pagingResponseStruct[dbObjectChange]:
+		isPagingType = true
 	}
 	req := getObjectRequest{
 		obj: obj.ObjectType(),
@@ -233,7 +355,8 @@ func (client *mockIBConnector) GetObject(obj ibclient.IBObject, ref string, quer
 					AsString(obj.(*ibclient.RecordA).Name) != AsString(object.(*ibclient.RecordA).Name) {
 					continue
 				}
-				if !strings.Contains(req.queryParams, fmt.Sprintf("ipv4addr:%s name:%s", AsString(object.(*ibclient.RecordA).Ipv4Addr), AsString(object.(*ibclient.RecordA).Name))) {
+				if !strings.Contains(req.queryParams, fmt.Sprintf("ipv4addr:%s name:%s", AsString(object.(*ibclient.RecordA).Ipv4Addr), AsString(object.(*ibclient.RecordA).Name))) &&
+					!strings.Contains(req.queryParams, fmt.Sprintf("[name:%s view:", AsString(object.(*ibclient.RecordA).Name))) {
 					if !strings.Contains(req.queryParams, fmt.Sprintf("zone:%s", object.(*ibclient.RecordA).Zone)) {
 						continue
 					}
@@ -246,6 +369,35 @@ func (client *mockIBConnector) GetObject(obj ibclient.IBObject, ref string, quer
 		} else {
 			*res.(*[]ibclient.RecordA) = result
 		}
+	case recordAAAA:
+		var result []ibclient.RecordAAAA
+		for _, object := range *client.mockInfobloxObjects {
+			if object.ObjectType() == recordAAAA {
+				if ref == object.(*ibclient.RecordAAAA).Ref {
+					result = append(result, *object.(*ibclient.RecordAAAA))
+				}
+				if ref != "" &&
+					ref != object.(*ibclient.RecordAAAA).Ref {
+					continue
+				}
+				if AsString(obj.(*ibclient.RecordAAAA).Name) != "" &&
+					AsString(obj.(*ibclient.RecordAAAA).Name) != AsString(object.(*ibclient.RecordAAAA).Name) {
+					continue
+				}
+				if !strings.Contains(req.queryParams, fmt.Sprintf("ipv6addr:%s name:%s", AsString(object.(*ibclient.RecordAAAA).Ipv6Addr), AsString(object.(*ibclient.RecordAAAA).Name))) &&
+					!strings.Contains(req.queryParams, fmt.Sprintf("[name:%s view:", AsString(object.(*ibclient.RecordAAAA).Name))) {
+					if !strings.Contains(req.queryParams, fmt.Sprintf("zone:%s", object.(*ibclient.RecordAAAA).Zone)) {
+						continue
+					}
+				}
+				result = append(result, *object.(*ibclient.RecordAAAA))
+			}
+		}
+		if isPagingType {
+			res.(*pagingResponseStruct[ibclient.RecordAAAA]).Result = result
+		} else {
+			*res.(*[]ibclient.RecordAAAA) = result
+		}
 	case recordCname:
 		var result []ibclient.RecordCNAME
 		for _, object := range *client.mockInfobloxObjects {
@@ -289,8 +441,10 @@ func (client *mockIBConnector) GetObject(obj ibclient.IBObject, ref string, quer
 					AsString(obj.(*ibclient.HostRecord).Name) != AsString(object.(*ibclient.HostRecord).Name) {
 					continue
 				}
-				if !strings.Contains(req.queryParams, fmt.Sprintf("ipv4addrs:%s name:%s", AsString(object.(*ibclient.HostRecord).Ipv4Addrs[0].Ipv4Addr), AsString(object.(*ibclient.HostRecord).Name))) {
-					if !strings.Contains(req.queryParams, fmt.Sprintf("zone:%s", object.(*ibclient.HostRecord).Zone)) {
+				if len(object.(*ibclient.HostRecord).Ipv4Addrs) == 0 ||
+					!strings.Contains(req.queryParams, fmt.Sprintf("ipv4addrs:%s name:%s", AsString(object.(*ibclient.HostRecord).Ipv4Addrs[0].Ipv4Addr), AsString(object.(*ibclient.HostRecord).Name))) {
+					if !strings.Contains(req.queryParams, fmt.Sprintf("zone:%s", object.(*ibclient.HostRecord).Zone)) &&
+						req.queryParams != fmt.Sprintf("&{false map[name:%s]}", AsString(object.(*ibclient.HostRecord).Name)) {
 						continue
 					}
 				}
@@ -317,7 +471,7 @@ func (client *mockIBConnector) GetObject(obj ibclient.IBObject, ref string, quer
 					AsString(obj.(*ibclient.RecordTXT).Name) != AsString(object.(*ibclient.RecordTXT).Name) {
 					continue
 				}
-				if !strings.Contains(req.queryParams, fmt.Sprintf("text:%s name:%s", AsString(object.(*ibclient.RecordTXT).Text), AsString(object.(*ibclient.RecordTXT).Name))) {
+				if !strings.Contains(req.queryParams, fmt.Sprintf("name:%s text:%s", AsString(object.(*ibclient.RecordTXT).Name), AsString(object.(*ibclient.RecordTXT).Text))) {
 					if !strings.Contains(req.queryParams, fmt.Sprintf("zone:%s", object.(*ibclient.RecordTXT).Zone)) {
 						continue
 					}
@@ -330,6 +484,119 @@ func (client *mockIBConnector) GetObject(obj ibclient.IBObject, ref string, quer
 		} else {
 			*res.(*[]ibclient.RecordTXT) = result
 		}
+	case recordMX:
+		var result []ibclient.RecordMX
+		for _, object := range *client.mockInfobloxObjects {
+			if object.ObjectType() == recordMX {
+				if ref == object.(*ibclient.RecordMX).Ref {
+					result = append(result, *object.(*ibclient.RecordMX))
+				}
+				if ref != "" &&
+					ref != object.(*ibclient.RecordMX).Ref {
+					continue
+				}
+				if AsString(obj.(*ibclient.RecordMX).Name) != "" &&
+					AsString(obj.(*ibclient.RecordMX).Name) != AsString(object.(*ibclient.RecordMX).Name) {
+					continue
+				}
+				if !strings.Contains(req.queryParams, fmt.Sprintf("mail_exchanger:%s name:%s preference:%d", AsString(object.(*ibclient.RecordMX).MailExchanger), AsString(object.(*ibclient.RecordMX).Name), AsInt64(object.(*ibclient.RecordMX).Preference))) {
+					if !strings.Contains(req.queryParams, fmt.Sprintf("zone:%s", object.(*ibclient.RecordMX).Zone)) {
+						continue
+					}
+				}
+				result = append(result, *object.(*ibclient.RecordMX))
+			}
+		}
+		if isPagingType {
+			res.(*pagingResponseStruct[ibclient.RecordMX]).Result = result
+		} else {
+			*res.(*[]ibclient.RecordMX) = result
+		}
+	case recordSRV:
+		var result []ibclient.RecordSRV
+		for _, object := range *client.mockInfobloxObjects {
+			if object.ObjectType() == recordSRV {
+				if ref == object.(*ibclient.RecordSRV).Ref {
+					result = append(result, *object.(*ibclient.RecordSRV))
+				}
+				if ref != "" &&
+					ref != object.(*ibclient.RecordSRV).Ref {
+					continue
+				}
+				if AsString(obj.(*ibclient.RecordSRV).Name) != "" &&
+					AsString(obj.(*ibclient.RecordSRV).Name) != AsString(object.(*ibclient.RecordSRV).Name) {
+					continue
+				}
+				srv := srvTargetFromRecord(object.(*ibclient.RecordSRV))
+				if !strings.Contains(req.queryParams, fmt.Sprintf("name:%s port:%d priority:%d target:%s weight:%d", AsString(object.(*ibclient.RecordSRV).Name), srv.Port, srv.Priority, srv.Target, srv.Weight)) {
+					if !strings.Contains(req.queryParams, fmt.Sprintf("zone:%s", object.(*ibclient.RecordSRV).Zone)) {
+						continue
+					}
+				}
+				result = append(result, *object.(*ibclient.RecordSRV))
+			}
+		}
+		if isPagingType {
+			res.(*pagingResponseStruct[ibclient.RecordSRV]).Result = result
+		} else {
+			*res.(*[]ibclient.RecordSRV) = result
+		}
+	case recordCAA:
+		var result []ibclient.RecordCaa
+		for _, object := range *client.mockInfobloxObjects {
+			if object.ObjectType() == recordCAA {
+				if ref == object.(*ibclient.RecordCaa).Ref {
+					result = append(result, *object.(*ibclient.RecordCaa))
+				}
+				if ref != "" &&
+					ref != object.(*ibclient.RecordCaa).Ref {
+					continue
+				}
+				if AsString(obj.(*ibclient.RecordCaa).Name) != "" &&
+					AsString(obj.(*ibclient.RecordCaa).Name) != AsString(object.(*ibclient.RecordCaa).Name) {
+					continue
+				}
+				if !strings.Contains(req.queryParams, fmt.Sprintf("ca_flag:%d ca_tag:%s ca_value:%s name:%s", AsInt64(object.(*ibclient.RecordCaa).CaFlag), AsString(object.(*ibclient.RecordCaa).CaTag), AsString(object.(*ibclient.RecordCaa).CaValue), AsString(object.(*ibclient.RecordCaa).Name))) {
+					if !strings.Contains(req.queryParams, fmt.Sprintf("zone:%s", object.(*ibclient.RecordCaa).Zone)) {
+						continue
+					}
+				}
+				result = append(result, *object.(*ibclient.RecordCaa))
+			}
+		}
+		if isPagingType {
+			res.(*pagingResponseStruct[ibclient.RecordCaa]).Result = result
+		} else {
+			*res.(*[]ibclient.RecordCaa) = result
+		}
+	case recordNS:
+		var result []ibclient.RecordNS
+		for _, object := range *client.mockInfobloxObjects {
+			if object.ObjectType() == recordNS {
+				if ref == object.(*ibclient.RecordNS).Ref {
+					result = append(result, *object.(*ibclient.RecordNS))
+				}
+				if ref != "" &&
+					ref != object.(*ibclient.RecordNS).Ref {
+					continue
+				}
+				if obj.(*ibclient.RecordNS).Name != "" &&
+					obj.(*ibclient.RecordNS).Name != object.(*ibclient.RecordNS).Name {
+					continue
+				}
+				if !strings.Contains(req.queryParams, fmt.Sprintf("name:%s nameserver:%s", object.(*ibclient.RecordNS).Name, AsString(object.(*ibclient.RecordNS).Nameserver))) {
+					if !strings.Contains(req.queryParams, fmt.Sprintf("zone:%s", object.(*ibclient.RecordNS).Zone)) {
+						continue
+					}
+				}
+				result = append(result, *object.(*ibclient.RecordNS))
+			}
+		}
+		if isPagingType {
+			res.(*pagingResponseStruct[ibclient.RecordNS]).Result = result
+		} else {
+			*res.(*[]ibclient.RecordNS) = result
+		}
 	case recordPtr:
 		var result []ibclient.RecordPTR
 		for _, object := range *client.mockInfobloxObjects {
@@ -345,7 +612,7 @@ func (client *mockIBConnector) GetObject(obj ibclient.IBObject, ref string, quer
 					AsString(obj.(*ibclient.RecordPTR).PtrdName) != AsString(object.(*ibclient.RecordPTR).PtrdName) {
 					continue
 				}
-				if !strings.Contains(req.queryParams, fmt.Sprintf("name:%s", AsString(object.(*ibclient.RecordPTR).Name))) {
+				if !strings.Contains(req.queryParams, fmt.Sprintf("addr:%s ptrdname:%s", ptrAddress(object.(*ibclient.RecordPTR)), AsString(object.(*ibclient.RecordPTR).PtrdName))) {
 					if !strings.Contains(req.queryParams, fmt.Sprintf("zone:%s", object.(*ibclient.RecordPTR).Zone)) {
 						continue
 					}
@@ -358,19 +625,46 @@ func (client *mockIBConnector) GetObject(obj ibclient.IBObject, ref string, quer
 		} else {
 			*res.(*[]ibclient.RecordPTR) = result
 		}
+	case "db_objects":
+		var result []dbObjectChange
+		start, _ := strconv.Atoi(req.url.Query().Get("start_sequence_id"))
+		for _, change := range client.dbObjectChanges {
+			if sequenceID, _ := strconv.Atoi(change.LastSequenceID); sequenceID > start {
+				result = append(result, change)
+			}
+		}
+		if isPagingType {
+			res.(*pagingResponseStruct[dbObjectChange]).Result = result
+		} else {
+			// WAPI reports the last sequence ID with every object, the last change has it
+			if req.url.Query().Get("_max_results") == "1" && len(result) > 0 {
+				result = result[len(result)-1:]
+			}
+			*res.(*[]dbObjectChange) = result
+		}
 	case "zone_auth":
 		if isPagingType {
 			res.(*pagingResponseStruct[ibclient.ZoneAuth]).Result = *client.mockInfobloxZones
 		} else {
 			*res.(*[]ibclient.ZoneAuth) = *client.mockInfobloxZones
 		}
+	case "grid":
+		if client.gridError != nil {
+			return client.gridError
+		}
+		name := "Infoblox"
+		*res.(*[]ibclient.Grid) = []ibclient.Grid{{Name: &name}}
 	}
 	return
 }
 
 func (client *mockIBConnector) DeleteObject(ref string) (refRes string, err error) {
+	client.deletedRefs = append(client.deletedRefs, ref)
 	re := regexp.MustCompile(`([^/]+)/[^:]+:([^/]+)/default`)
 	result := re.FindStringSubmatch(ref)
+	if err, ok := client.deleteErrors[result[2]]; ok {
+		return "", err
+	}
 
 	switch result[1] {
 	case "record:a":
@@ -388,6 +682,21 @@ func (client *mockIBConnector) DeleteObject(ref string) (refRes string, err erro
 				),
 			)
 		}
+	case "record:aaaa":
+		var records []ibclient.RecordAAAA
+		obj := ibclient.NewEmptyRecordAAAA()
+		obj.Name = &result[2]
+		client.GetObject(obj, ref, nil, &records) // nolint: errcheck
+		for _, record := range records {
+			client.deletedEndpoints = append(
+				client.deletedEndpoints,
+				endpoint.NewEndpoint(
+					*record.Name,
+					endpoint.RecordTypeAAAA,
+					"",
+				),
+			)
+		}
 	case "record:cname":
 		var records []ibclient.RecordCNAME
 		obj := ibclient.NewEmptyRecordCNAME()
@@ -433,6 +742,66 @@ func (client *mockIBConnector) DeleteObject(ref string) (refRes string, err erro
 				),
 			)
 		}
+	case "record:mx":
+		var records []ibclient.RecordMX
+		obj := ibclient.NewEmptyRecordMX()
+		obj.Name = &result[2]
+		client.GetObject(obj, ref, nil, &records) // nolint: errcheck
+		for _, record := range records {
+			client.deletedEndpoints = append(
+				client.deletedEndpoints,
+				endpoint.NewEndpoint(
+					*record.Name,
+					endpoint.RecordTypeMX,
+					"",
+				),
+			)
+		}
+	case "record:srv":
+		var records []ibclient.RecordSRV
+		obj := ibclient.NewEmptyRecordSRV()
+		obj.Name = &result[2]
+		client.GetObject(obj, ref, nil, &records) // nolint: errcheck
+		for _, record := range records {
+			client.deletedEndpoints = append(
+				client.deletedEndpoints,
+				endpoint.NewEndpoint(
+					*record.Name,
+					endpoint.RecordTypeSRV,
+					"",
+				),
+			)
+		}
+	case "record:caa":
+		var records []ibclient.RecordCaa
+		obj := newEmptyRecordCAA()
+		obj.Name = &result[2]
+		client.GetObject(obj, ref, nil, &records) // nolint: errcheck
+		for _, record := range records {
+			client.deletedEndpoints = append(
+				client.deletedEndpoints,
+				endpoint.NewEndpoint(
+					*record.Name,
+					recordTypeCAA,
+					"",
+				),
+			)
+		}
+	case "record:ns":
+		var records []ibclient.RecordNS
+		obj := newEmptyRecordNS()
+		obj.Name = result[2]
+		client.GetObject(obj, ref, nil, &records) // nolint: errcheck
+		for _, record := range records {
+			client.deletedEndpoints = append(
+				client.deletedEndpoints,
+				endpoint.NewEndpoint(
+					record.Name,
+					endpoint.RecordTypeNS,
+					"",
+				),
+			)
+		}
 	case "record:ptr":
 		var records []ibclient.RecordPTR
 		obj := ibclient.NewEmptyRecordPTR()
@@ -453,14 +822,24 @@ func (client *mockIBConnector) DeleteObject(ref string) (refRes string, err erro
 }
 
 func (client *mockIBConnector) UpdateObject(obj ibclient.IBObject, ref string) (refRes string, err error) {
+	client.updatedObjects = append(client.updatedObjects, obj)
 	switch obj.ObjectType() {
 	case "record:a":
 		client.updatedEndpoints = append(
 			client.updatedEndpoints,
 			endpoint.NewEndpoint(
 				*obj.(*ibclient.RecordA).Name,
-				*obj.(*ibclient.RecordA).Ipv4Addr,
 				endpoint.RecordTypeA,
+				*obj.(*ibclient.RecordA).Ipv4Addr,
+			),
+		)
+	case "record:aaaa":
+		client.updatedEndpoints = append(
+			client.updatedEndpoints,
+			endpoint.NewEndpoint(
+				*obj.(*ibclient.RecordAAAA).Name,
+				endpoint.RecordTypeAAAA,
+				*obj.(*ibclient.RecordAAAA).Ipv6Addr,
 			),
 		)
 	case "record:cname":
@@ -468,8 +847,8 @@ func (client *mockIBConnector) UpdateObject(obj ibclient.IBObject, ref string) (
 			client.updatedEndpoints,
 			endpoint.NewEndpoint(
 				*obj.(*ibclient.RecordCNAME).Name,
-				*obj.(*ibclient.RecordCNAME).Canonical,
 				endpoint.RecordTypeCNAME,
+				*obj.(*ibclient.RecordCNAME).Canonical,
 			),
 		)
 	case "record:host":
@@ -478,18 +857,62 @@ func (client *mockIBConnector) UpdateObject(obj ibclient.IBObject, ref string) (
 				client.updatedEndpoints,
 				endpoint.NewEndpoint(
 					*obj.(*ibclient.HostRecord).Name,
-					*i.Ipv4Addr,
 					endpoint.RecordTypeA,
+					*i.Ipv4Addr,
+				),
+			)
+		}
+		for _, i := range obj.(*ibclient.HostRecord).Ipv6Addrs {
+			client.updatedEndpoints = append(
+				client.updatedEndpoints,
+				endpoint.NewEndpoint(
+					*obj.(*ibclient.HostRecord).Name,
+					endpoint.RecordTypeAAAA,
+					*i.Ipv6Addr,
 				),
 			)
 		}
+		// hosts are updated address by address, so later lookups have to see the new addresses
+		for _, object := range *client.mockInfobloxObjects {
+			if host, ok := object.(*ibclient.HostRecord); ok && host.Ref == ref {
+				host.Ipv4Addrs = obj.(*ibclient.HostRecord).Ipv4Addrs
+				host.Ipv6Addrs = obj.(*ibclient.HostRecord).Ipv6Addrs
+			}
+		}
 	case "record:txt":
 		client.updatedEndpoints = append(
 			client.updatedEndpoints,
 			endpoint.NewEndpoint(
 				*obj.(*ibclient.RecordTXT).Name,
-				*obj.(*ibclient.RecordTXT).Text,
 				endpoint.RecordTypeTXT,
+				*obj.(*ibclient.RecordTXT).Text,
+			),
+		)
+	case "record:mx":
+		client.updatedEndpoints = append(
+			client.updatedEndpoints,
+			endpoint.NewEndpoint(
+				*obj.(*ibclient.RecordMX).Name,
+				endpoint.RecordTypeMX,
+				formatMXTarget(AsInt64(obj.(*ibclient.RecordMX).Preference), *obj.(*ibclient.RecordMX).MailExchanger),
+			),
+		)
+	case "record:srv":
+		client.updatedEndpoints = append(
+			client.updatedEndpoints,
+			endpoint.NewEndpoint(
+				*obj.(*ibclient.RecordSRV).Name,
+				endpoint.RecordTypeSRV,
+				srvTargetFromRecord(obj.(*ibclient.RecordSRV)).String(),
+			),
+		)
+	case "record:caa":
+		client.updatedEndpoints = append(
+			client.updatedEndpoints,
+			endpoint.NewEndpoint(
+				*obj.(*ibclient.RecordCaa).Name,
+				recordTypeCAA,
+				caaTargetFromRecord(obj.(*ibclient.RecordCaa)).String(),
 			),
 		)
 	}
@@ -512,6 +935,13 @@ func createMockInfobloxObjectWithZone(name, recordType, value, zone string) ibcl
 		obj.Ipv4Addr = &value
 		obj.Zone = zone
 		return obj
+	case endpoint.RecordTypeAAAA:
+		obj := ibclient.NewEmptyRecordAAAA()
+		obj.Name = &name
+		obj.Ref = ref
+		obj.Ipv6Addr = &value
+		obj.Zone = zone
+		return obj
 	case endpoint.RecordTypeCNAME:
 		obj := ibclient.NewEmptyRecordCNAME()
 		obj.Name = &name
@@ -526,22 +956,52 @@ func createMockInfobloxObjectWithZone(name, recordType, value, zone string) ibcl
 		obj.Text = &value
 		obj.Zone = zone
 		return obj
+	case endpoint.RecordTypeMX:
+		obj := createMockMXRecord(name, value)
+		obj.Ref = ref
+		obj.Zone = zone
+		return obj
+	case endpoint.RecordTypeSRV:
+		obj := createMockSRVRecord(name, value)
+		obj.Ref = ref
+		obj.Zone = zone
+		return obj
+	case endpoint.RecordTypeNS:
+		obj := newEmptyRecordNS()
+		obj.Name = name
+		obj.Ref = ref
+		obj.Nameserver = &value
+		obj.Zone = zone
+		return obj
+	case recordTypeCAA:
+		obj := createMockCAARecord(name, value)
+		obj.Ref = ref
+		obj.Zone = zone
+		return obj
 	case "HOST":
 		obj := ibclient.NewEmptyHostRecord()
 		obj.Name = &name
 		obj.Ref = ref
-		obj.Ipv4Addrs = []ibclient.HostRecordIpv4Addr{
-			{
-				Ipv4Addr: &value,
-			},
+		if net.ParseIP(value).To4() == nil {
+			obj.Ipv6Addrs = []ibclient.HostRecordIpv6Addr{
+				{
+					Ipv6Addr: &value,
+				},
+			}
+		} else {
+			obj.Ipv4Addrs = []ibclient.HostRecordIpv4Addr{
+				{
+					Ipv4Addr: &value,
+				},
+			}
 		}
 		obj.Zone = zone
 		return obj
 	case endpoint.RecordTypePTR:
 		obj := ibclient.NewEmptyRecordPTR()
 		obj.PtrdName = &name
-		obj.Ref = ref
-		obj.Ipv4Addr = &value
+		obj.Ref = createMockPTRRef(name, value)
+		setMockPTRAddress(obj, value)
 		obj.Zone = zone
 		return obj
 	}
@@ -558,6 +1018,12 @@ func createMockInfobloxObject(name, recordType, value string) ibclient.IBObject
 		obj.Ref = ref
 		obj.Ipv4Addr = &value
 		return obj
+	case endpoint.RecordTypeAAAA:
+		obj := ibclient.NewEmptyRecordAAAA()
+		obj.Name = &name
+		obj.Ref = ref
+		obj.Ipv6Addr = &value
+		return obj
 	case endpoint.RecordTypeCNAME:
 		obj := ibclient.NewEmptyRecordCNAME()
 		obj.Name = &name
@@ -570,6 +1036,24 @@ func createMockInfobloxObject(name, recordType, value string) ibclient.IBObject
 		obj.Ref = ref
 		obj.Text = &value
 		return obj
+	case endpoint.RecordTypeMX:
+		obj := createMockMXRecord(name, value)
+		obj.Ref = ref
+		return obj
+	case endpoint.RecordTypeSRV:
+		obj := createMockSRVRecord(name, value)
+		obj.Ref = ref
+		return obj
+	case endpoint.RecordTypeNS:
+		obj := newEmptyRecordNS()
+		obj.Name = name
+		obj.Ref = ref
+		obj.Nameserver = &value
+		return obj
+	case recordTypeCAA:
+		obj := createMockCAARecord(name, value)
+		obj.Ref = ref
+		return obj
 	case "HOST":
 		obj := ibclient.NewEmptyHostRecord()
 		obj.Name = &name
@@ -583,14 +1067,60 @@ func createMockInfobloxObject(name, recordType, value string) ibclient.IBObject
 	case endpoint.RecordTypePTR:
 		obj := ibclient.NewEmptyRecordPTR()
 		obj.PtrdName = &name
-		obj.Ref = ref
-		obj.Ipv4Addr = &value
+		obj.Ref = createMockPTRRef(name, value)
+		setMockPTRAddress(obj, value)
 		return obj
 	}
 
 	return nil
 }
 
+func createMockMXRecord(name, value string) *ibclient.RecordMX {
+	preference, exchanger, _ := parseMXTarget(value)
+	pref := uint32(preference)
+	obj := ibclient.NewEmptyRecordMX()
+	obj.Name = &name
+	obj.Preference = &pref
+	obj.MailExchanger = &exchanger
+	return obj
+}
+
+func createMockSRVRecord(name, value string) *ibclient.RecordSRV {
+	srv, _ := parseSRVTarget(value)
+	obj := ibclient.NewEmptyRecordSRV()
+	obj.Name = &name
+	obj.Priority = &srv.Priority
+	obj.Weight = &srv.Weight
+	obj.Port = &srv.Port
+	obj.Target = &srv.Target
+	return obj
+}
+
+func createMockCAARecord(name, value string) *ibclient.RecordCaa {
+	caa, _ := parseCAATarget(value)
+	obj := newEmptyRecordCAA()
+	obj.Name = &name
+	obj.CaFlag = &caa.Flag
+	obj.CaTag = &caa.Tag
+	obj.CaValue = &caa.Value
+	return obj
+}
+
+// createMockPTRRef builds the reference of a PTR record from its address, so
+// PTR records sharing a ptrdname (IPv4 and IPv6) stay distinguishable
+func createMockPTRRef(name, value string) string {
+	reverseAddr, _ := dns.ReverseAddr(value)
+	return fmt.Sprintf("%s/%s:%s/default", recordPtr, base64.StdEncoding.EncodeToString([]byte(name)), reverseAddr)
+}
+
+func setMockPTRAddress(obj *ibclient.RecordPTR, value string) {
+	if net.ParseIP(value).To4() == nil {
+		obj.Ipv6Addr = &value
+		return
+	}
+	obj.Ipv4Addr = &value
+}
+
 // nolint: unparam
 func newInfobloxProvider(domainFilter endpoint.DomainFilter, zoneIDFilter provider.ZoneIDFilter, view string, dryRun bool, createPTR bool, client ibclient.IBConnector) *Provider {
 	return &Provider{
@@ -625,6 +1155,20 @@ func TestInfobloxRecords(t *testing.T) {
 			createMockInfobloxObjectWithZone("existing.example.com", endpoint.RecordTypeA, "124.1.1.2", "example.com"),
 			createMockInfobloxObjectWithZone("existing.example.com", endpoint.RecordTypeTXT, "heritage=external-dns,external-dns/owner=existing", "example.com"),
 			createMockInfobloxObjectWithZone("host.example.com", "HOST", "125.1.1.1", "example.com"),
+			createMockInfobloxObjectWithZone("dualstack.example.com", endpoint.RecordTypeA, "123.123.123.125", "example.com"),
+			createMockInfobloxObjectWithZone("dualstack.example.com", endpoint.RecordTypeAAAA, "2001:db8::1", "example.com"),
+			createMockInfobloxObjectWithZone("dualstack.example.com", endpoint.RecordTypeAAAA, "2001:db8::2", "example.com"),
+			createMockInfobloxObjectWithZone("dualstack.example.com", endpoint.RecordTypeTXT, "heritage=external-dns,external-dns/owner=default", "example.com"),
+			createMockInfobloxObjectWithZone("host6.example.com", "HOST", "2001:db8::3", "example.com"),
+			createMockInfobloxObjectWithZone("example.com", endpoint.RecordTypeMX, "10 mail.example.com", "example.com"),
+			createMockInfobloxObjectWithZone("example.com", endpoint.RecordTypeMX, "20 mail2.example.com", "example.com"),
+			createMockInfobloxObjectWithZone("_sip._tcp.example.com", endpoint.RecordTypeSRV, "10 60 5060 sip.example.com", "example.com"),
+			createMockInfobloxObjectWithZone("_sip._tcp.example.com", endpoint.RecordTypeSRV, "20 0 5060 sip2.example.com", "example.com"),
+			createMockInfobloxObjectWithZone("example.com", endpoint.RecordTypeNS, "ns1.grid.example.org", "example.com"),
+			createMockInfobloxObjectWithZone("example.com", recordTypeCAA, `0 issue "letsencrypt.org"`, "example.com"),
+			createMockInfobloxObjectWithZone("example.com", recordTypeCAA, `0 iodef "mailto:security@example.com"`, "example.com"),
+			createMockInfobloxObjectWithZone("cluster1.example.com", endpoint.RecordTypeNS, "ns1.cluster1.example.com", "example.com"),
+			createMockInfobloxObjectWithZone("cluster1.example.com", endpoint.RecordTypeNS, "ns2.cluster1.example.com", "example.com"),
 		},
 	}
 
@@ -645,7 +1189,15 @@ func TestInfobloxRecords(t *testing.T) {
 		endpoint.NewEndpoint("multiple.example.com", endpoint.RecordTypeTXT, "heritage=external-dns,external-dns/owner=default"),
 		endpoint.NewEndpoint("existing.example.com", endpoint.RecordTypeA, "124.1.1.1", "124.1.1.2"),
 		endpoint.NewEndpoint("existing.example.com", endpoint.RecordTypeTXT, "heritage=external-dns,external-dns/owner=existing"),
-		endpoint.NewEndpoint("host.example.com", endpoint.RecordTypeA, "125.1.1.1"),
+		endpoint.NewEndpoint("host.example.com", endpoint.RecordTypeA, "125.1.1.1").WithProviderSpecific(providerSpecificInfobloxHostRecord, "true"),
+		endpoint.NewEndpoint("dualstack.example.com", endpoint.RecordTypeA, "123.123.123.125"),
+		endpoint.NewEndpoint("dualstack.example.com", endpoint.RecordTypeAAAA, "2001:db8::1", "2001:db8::2"),
+		endpoint.NewEndpoint("dualstack.example.com", endpoint.RecordTypeTXT, "heritage=external-dns,external-dns/owner=default"),
+		endpoint.NewEndpoint("host6.example.com", endpoint.RecordTypeAAAA, "2001:db8::3").WithProviderSpecific(providerSpecificInfobloxHostRecord, "true"),
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeMX, "10 mail.example.com", "20 mail2.example.com"),
+		endpoint.NewEndpoint("_sip._tcp.example.com", endpoint.RecordTypeSRV, "10 60 5060 sip.example.com", "20 0 5060 sip2.example.com"),
+		endpoint.NewEndpoint("cluster1.example.com", endpoint.RecordTypeNS, "ns1.cluster1.example.com", "ns2.cluster1.example.com"),
+		endpoint.NewEndpoint("example.com", recordTypeCAA, `0 iodef "mailto:security@example.com"`, `0 issue "letsencrypt.org"`),
 	}
 	validateEndpoints(t, actual, expected)
 	client.verifyGetObjectRequest(t, "zone_auth", "", &map[string]string{
@@ -661,6 +1213,13 @@ func TestInfobloxRecords(t *testing.T) {
 		"view":              "",
 		"zone":              "example.com"}).
 		ExpectRequestURLQueryParam(t, "zone", "example.com")
+	client.verifyGetObjectRequest(t, "record:aaaa", "", &map[string]string{
+		"_max_results":      "1000",
+		"_paging":           "1",
+		"_return_as_object": "1",
+		"view":              "",
+		"zone":              "example.com"}).
+		ExpectRequestURLQueryParam(t, "zone", "example.com")
 	client.verifyGetObjectRequest(t, "record:host", "", &map[string]string{
 		"_max_results":      "1000",
 		"_paging":           "1",
@@ -682,6 +1241,36 @@ func TestInfobloxRecords(t *testing.T) {
 		"view":              "",
 		"zone":              "example.com"}).
 		ExpectRequestURLQueryParam(t, "zone", "example.com")
+	client.verifyGetObjectRequest(t, "record:mx", "", &map[string]string{
+		"_max_results":      "1000",
+		"_paging":           "1",
+		"_return_as_object": "1",
+		"view":              "",
+		"zone":              "example.com"}).
+		ExpectRequestURLQueryParam(t, "zone", "example.com")
+	client.verifyGetObjectRequest(t, "record:srv", "", &map[string]string{
+		"_max_results":      "1000",
+		"_paging":           "1",
+		"_return_as_object": "1",
+		"view":              "",
+		"zone":              "example.com"}).
+		ExpectRequestURLQueryParam(t, "zone", "example.com")
+	client.verifyGetObjectRequest(t, "record:ns", "", &map[string]string{
+		"_max_results":      "1000",
+		"_paging":           "1",
+		"_return_as_object": "1",
+		"view":              "",
+		"zone":              "example.com"}).
+		ExpectRequestURLQueryParam(t, "zone", "example.com").
+		ExpectRequestURLQueryParam(t, "_return_fields", "name,nameserver,addresses,view,zone")
+	client.verifyGetObjectRequest(t, "record:caa", "", &map[string]string{
+		"_max_results":      "1000",
+		"_paging":           "1",
+		"_return_as_object": "1",
+		"view":              "",
+		"zone":              "example.com"}).
+		ExpectRequestURLQueryParam(t, "zone", "example.com").
+		ExpectRequestURLQueryParam(t, "_return_fields", "name,ca_flag,ca_tag,ca_value,ttl,use_ttl,view,zone,extattrs")
 	client.verifyNoMoreGetObjectRequests(t)
 }
 
@@ -722,6 +1311,13 @@ func TestInfobloxRecordsWithView(t *testing.T) {
 		"view":              "Inside"}).
 		ExpectRequestURLQueryParam(t, "zone", "foo.example.com").
 		ExpectRequestURLQueryParam(t, "view", "Inside")
+	client.verifyGetObjectRequest(t, "record:aaaa", "", &map[string]string{
+		"_max_results":      "1000",
+		"_paging":           "1",
+		"_return_as_object": "1",
+		"zone":              "foo.example.com", "view": "Inside"}).
+		ExpectRequestURLQueryParam(t, "zone", "foo.example.com").
+		ExpectRequestURLQueryParam(t, "view", "Inside")
 	client.verifyGetObjectRequest(t, "record:host", "", &map[string]string{
 		"_max_results":      "1000",
 		"_paging":           "1",
@@ -743,12 +1339,46 @@ func TestInfobloxRecordsWithView(t *testing.T) {
 		"zone":              "foo.example.com", "view": "Inside"}).
 		ExpectRequestURLQueryParam(t, "zone", "foo.example.com").
 		ExpectRequestURLQueryParam(t, "view", "Inside")
+	client.verifyGetObjectRequest(t, "record:mx", "", &map[string]string{
+		"_max_results":      "1000",
+		"_paging":           "1",
+		"_return_as_object": "1",
+		"zone":              "foo.example.com", "view": "Inside"}).
+		ExpectRequestURLQueryParam(t, "zone", "foo.example.com").
+		ExpectRequestURLQueryParam(t, "view", "Inside")
+	client.verifyGetObjectRequest(t, "record:srv", "", &map[string]string{
+		"_max_results":      "1000",
+		"_paging":           "1",
+		"_return_as_object": "1",
+		"zone":              "foo.example.com", "view": "Inside"}).
+		ExpectRequestURLQueryParam(t, "zone", "foo.example.com").
+		ExpectRequestURLQueryParam(t, "view", "Inside")
+	client.verifyGetObjectRequest(t, "record:ns", "", &map[string]string{
+		"_max_results":      "1000",
+		"_paging":           "1",
+		"_return_as_object": "1",
+		"zone":              "foo.example.com", "view": "Inside"}).
+		ExpectRequestURLQueryParam(t, "zone", "foo.example.com").
+		ExpectRequestURLQueryParam(t, "view", "Inside")
+	client.verifyGetObjectRequest(t, "record:caa", "", &map[string]string{
+		"_max_results":      "1000",
+		"_paging":           "1",
+		"_return_as_object": "1",
+		"zone":              "foo.example.com", "view": "Inside"}).
+		ExpectRequestURLQueryParam(t, "zone", "foo.example.com").
+		ExpectRequestURLQueryParam(t, "view", "Inside")
 	client.verifyGetObjectRequest(t, "record:a", "", &map[string]string{
 		"_max_results":      "1000",
 		"_paging":           "1",
 		"_return_as_object": "1", "zone": "bar.example.com", "view": "Inside"}).
 		ExpectRequestURLQueryParam(t, "zone", "bar.example.com").
 		ExpectRequestURLQueryParam(t, "view", "Inside")
+	client.verifyGetObjectRequest(t, "record:aaaa", "", &map[string]string{
+		"_max_results":      "1000",
+		"_paging":           "1",
+		"_return_as_object": "1", "zone": "bar.example.com", "view": "Inside"}).
+		ExpectRequestURLQueryParam(t, "zone", "bar.example.com").
+		ExpectRequestURLQueryParam(t, "view", "Inside")
 	client.verifyGetObjectRequest(t, "record:host", "", &map[string]string{
 		"_max_results":      "1000",
 		"_paging":           "1",
@@ -768,6 +1398,34 @@ func TestInfobloxRecordsWithView(t *testing.T) {
 		"zone":              "bar.example.com", "view": "Inside"}).
 		ExpectRequestURLQueryParam(t, "zone", "bar.example.com").
 		ExpectRequestURLQueryParam(t, "view", "Inside")
+	client.verifyGetObjectRequest(t, "record:mx", "", &map[string]string{
+		"_max_results":      "1000",
+		"_paging":           "1",
+		"_return_as_object": "1",
+		"zone":              "bar.example.com", "view": "Inside"}).
+		ExpectRequestURLQueryParam(t, "zone", "bar.example.com").
+		ExpectRequestURLQueryParam(t, "view", "Inside")
+	client.verifyGetObjectRequest(t, "record:srv", "", &map[string]string{
+		"_max_results":      "1000",
+		"_paging":           "1",
+		"_return_as_object": "1",
+		"zone":              "bar.example.com", "view": "Inside"}).
+		ExpectRequestURLQueryParam(t, "zone", "bar.example.com").
+		ExpectRequestURLQueryParam(t, "view", "Inside")
+	client.verifyGetObjectRequest(t, "record:ns", "", &map[string]string{
+		"_max_results":      "1000",
+		"_paging":           "1",
+		"_return_as_object": "1",
+		"zone":              "bar.example.com", "view": "Inside"}).
+		ExpectRequestURLQueryParam(t, "zone", "bar.example.com").
+		ExpectRequestURLQueryParam(t, "view", "Inside")
+	client.verifyGetObjectRequest(t, "record:caa", "", &map[string]string{
+		"_max_results":      "1000",
+		"_paging":           "1",
+		"_return_as_object": "1",
+		"zone":              "bar.example.com", "view": "Inside"}).
+		ExpectRequestURLQueryParam(t, "zone", "bar.example.com").
+		ExpectRequestURLQueryParam(t, "view", "Inside")
 	client.verifyNoMoreGetObjectRequests(t)
 }
 
@@ -782,6 +1440,7 @@ func TestInfobloxAdjustEndpoints(t *testing.T) {
 			createMockInfobloxObject("example.com", endpoint.RecordTypeTXT, "heritage=external-dns,external-dns/owner=default"),
 			createMockInfobloxObject("hack.example.com", endpoint.RecordTypeCNAME, "cerberus.infoblox.com"),
 			createMockInfobloxObject("host.example.com", "HOST", "125.1.1.1"),
+			createMockInfobloxObject("ipv6.example.com", endpoint.RecordTypeAAAA, "2001:db8::1"),
 		},
 	}
 
@@ -796,11 +1455,156 @@ func TestInfobloxAdjustEndpoints(t *testing.T) {
 		endpoint.NewEndpoint("example.com", endpoint.RecordTypeA, "123.123.123.122").WithProviderSpecific(providerSpecificInfobloxPtrRecord, "true"),
 		endpoint.NewEndpoint("example.com", endpoint.RecordTypeTXT, "heritage=external-dns,external-dns/owner=default"),
 		endpoint.NewEndpoint("hack.example.com", endpoint.RecordTypeCNAME, "cerberus.infoblox.com"),
-		endpoint.NewEndpoint("host.example.com", endpoint.RecordTypeA, "125.1.1.1").WithProviderSpecific(providerSpecificInfobloxPtrRecord, "true"),
+		endpoint.NewEndpoint("host.example.com", endpoint.RecordTypeA, "125.1.1.1").WithProviderSpecific(providerSpecificInfobloxHostRecord, "true").WithProviderSpecific(providerSpecificInfobloxPtrRecord, "true"),
+		endpoint.NewEndpoint("ipv6.example.com", endpoint.RecordTypeAAAA, "2001:db8::1").WithProviderSpecific(providerSpecificInfobloxPtrRecord, "true"),
 	}
 	validateEndpoints(t, actual, expected)
 }
 
+func TestInfobloxAdjustEndpointsMX(t *testing.T) {
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", true, false, &mockIBConnector{})
+
+	actual, err := providerCfg.AdjustEndpoints([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeMX, "10  mail.example.com.", "20 mail2.example.com"),
+	})
+	assert.NoError(t, err)
+	validateEndpoints(t, actual, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("example.com", endpoint.RecordTypeMX, 0, "10 mail.example.com", "20 mail2.example.com"),
+	})
+
+	for _, target := range []string{"mail.example.com", "ten mail.example.com", "70000 mail.example.com", "10 mail.example.com extra"} {
+		_, err = providerCfg.AdjustEndpoints([]*endpoint.Endpoint{
+			endpoint.NewEndpoint("example.com", endpoint.RecordTypeMX, target),
+		})
+		assert.Error(t, err, target)
+	}
+}
+
+func TestInfobloxAdjustEndpointsSRV(t *testing.T) {
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", true, false, &mockIBConnector{})
+
+	actual, err := providerCfg.AdjustEndpoints([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("_ldap._tcp.example.com", endpoint.RecordTypeSRV, "10 60  389 ldap.example.com.", "20 0 389 ldap2.example.com"),
+	})
+	assert.NoError(t, err)
+	validateEndpoints(t, actual, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("_ldap._tcp.example.com", endpoint.RecordTypeSRV, 0, "10 60 389 ldap.example.com", "20 0 389 ldap2.example.com"),
+	})
+
+	for _, target := range []string{
+		"ldap.example.com",
+		"10 389 ldap.example.com",
+		"10 60 389 ldap.example.com extra",
+		"ten 60 389 ldap.example.com",
+		"10 -1 389 ldap.example.com",
+		"10 60 65536 ldap.example.com",
+		"10 60 389 .",
+	} {
+		_, err = providerCfg.AdjustEndpoints([]*endpoint.Endpoint{
+			endpoint.NewEndpoint("_ldap._tcp.example.com", endpoint.RecordTypeSRV, target),
+		})
+		assert.Error(t, err, target)
+	}
+}
+
+func TestInfobloxAdjustEndpointsCAA(t *testing.T) {
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", true, false, &mockIBConnector{})
+
+	actual, err := providerCfg.AdjustEndpoints([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("example.com", recordTypeCAA, "0 issue letsencrypt.org", `128  ISSUEWILD "digicert.com; cansignhttpexchanges=yes"`, `0 issue ";"`),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, endpoint.Targets{`0 issue "letsencrypt.org"`, `128 issuewild "digicert.com; cansignhttpexchanges=yes"`, `0 issue ";"`}, actual[0].Targets)
+
+	for _, target := range []string{
+		"letsencrypt.org",
+		"0 issue",
+		"256 issue letsencrypt.org",
+		"-1 issue letsencrypt.org",
+		"zero issue letsencrypt.org",
+		"0 is-sue letsencrypt.org",
+	} {
+		_, err = providerCfg.AdjustEndpoints([]*endpoint.Endpoint{
+			endpoint.NewEndpoint("example.com", recordTypeCAA, target),
+		})
+		assert.Error(t, err, target)
+	}
+}
+
+func TestInfobloxAdjustEndpointsNS(t *testing.T) {
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", true, false, &mockIBConnector{})
+	providerCfg.config.DefaultTTL = 300
+
+	actual, err := providerCfg.AdjustEndpoints([]*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("cluster1.example.com", endpoint.RecordTypeNS, 3600, "ns1.cluster1.example.com"),
+		endpoint.NewEndpoint("nginx.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, endpoint.TTL(0), actual[0].RecordTTL)
+	assert.Equal(t, endpoint.TTL(300), actual[1].RecordTTL)
+}
+
+func TestInfobloxApplyChangesNS(t *testing.T) {
+	client := mockIBConnector{
+		mockInfobloxZones: &[]ibclient.ZoneAuth{
+			createMockInfobloxZone("example.com"),
+			createMockInfobloxZone("cluster1.example.com"),
+		},
+		mockInfobloxObjects: &[]ibclient.IBObject{
+			createMockInfobloxObjectWithZone("ns1.cluster1.example.com", endpoint.RecordTypeA, "10.0.0.53", "cluster1.example.com"),
+			createMockInfobloxObjectWithZone("ns1.cluster1.example.com", endpoint.RecordTypeAAAA, "2001:db8::53", "cluster1.example.com"),
+			createMockInfobloxObjectWithZone("cluster2.example.com", endpoint.RecordTypeNS, "ns1.cluster2.example.com", "example.com"),
+		},
+	}
+
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, &client)
+
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("cluster1.example.com", endpoint.RecordTypeNS, "ns1.cluster1.example.com"),
+		},
+		Delete: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("cluster2.example.com", endpoint.RecordTypeNS, "ns1.cluster2.example.com"),
+		},
+	}
+	assert.NoError(t, providerCfg.ApplyChanges(context.Background(), changes))
+
+	validateEndpoints(t, client.createdEndpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("cluster1.example.com", endpoint.RecordTypeNS, "ns1.cluster1.example.com"),
+	})
+	validateEndpoints(t, client.deletedEndpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("cluster2.example.com", endpoint.RecordTypeNS, ""),
+	})
+
+	assert.Len(t, client.createdObjects, 1)
+	created := client.createdObjects[0].(*ibclient.RecordNS)
+	assert.Equal(t, "cluster1.example.com", created.Name)
+	var addresses []string
+	for _, address := range created.Addresses {
+		addresses = append(addresses, address.Address)
+	}
+	assert.ElementsMatch(t, []string{"10.0.0.53", "2001:db8::53"}, addresses)
+}
+
+func TestInfobloxFindParentZone(t *testing.T) {
+	client := mockIBConnector{
+		mockInfobloxZones: &[]ibclient.ZoneAuth{
+			createMockInfobloxZone("example.com"),
+			createMockInfobloxZone("cluster1.example.com"),
+		},
+		mockInfobloxObjects: &[]ibclient.IBObject{},
+	}
+
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", true, false, &client)
+	zoneAuths, _ := providerCfg.zones(context.Background())
+	zones := zonePointerConverter(zoneAuths)
+	var emptyZoneAuth *ibclient.ZoneAuth
+	assert.Equal(t, "cluster1.example.com", providerCfg.findZone(zones, "cluster1.example.com").Fqdn)
+	assert.Equal(t, "example.com", providerCfg.findParentZone(zones, "cluster1.example.com").Fqdn)
+	assert.Equal(t, "example.com", providerCfg.findParentZone(zones, "cluster2.example.com").Fqdn)
+	assert.Equal(t, emptyZoneAuth, providerCfg.findParentZone(zones, "example.com"))
+}
+
 func TestInfobloxRecordsReverse(t *testing.T) {
 	client := mockIBConnector{
 		mockInfobloxZones: &[]ibclient.ZoneAuth{
@@ -825,6 +1629,38 @@ func TestInfobloxRecordsReverse(t *testing.T) {
 	validateEndpoints(t, actual, expected)
 }
 
+func TestInfobloxRecordsReverseIPv6(t *testing.T) {
+	client := mockIBConnector{
+		mockInfobloxZones: &[]ibclient.ZoneAuth{
+			createMockInfobloxZone("example.com"),
+			createMockInfobloxZone("2001:db8::/48"),
+		},
+		mockInfobloxObjects: &[]ibclient.IBObject{
+			createMockInfobloxObjectWithZone("example.com", endpoint.RecordTypeAAAA, "2001:db8::1", "example.com"),
+			createMockInfobloxObjectWithZone("example.com", endpoint.RecordTypePTR, "2001:db8::1", "0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa"),
+			createMockInfobloxObjectWithZone("other.example.com", endpoint.RecordTypeAAAA, "2001:db8::2", "example.com"),
+		},
+	}
+
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com", "2001:db8::/48"}), provider.NewZoneIDFilter([]string{""}), "", true, true, &client)
+	actual, err := providerCfg.Records(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeAAAA, "2001:db8::1").WithProviderSpecific(providerSpecificInfobloxPtrRecord, "true"),
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypePTR, "2001:db8::1"),
+		endpoint.NewEndpoint("other.example.com", endpoint.RecordTypeAAAA, "2001:db8::2"),
+	}
+	validateEndpoints(t, actual, expected)
+	client.verifyGetObjectRequest(t, "record:ptr", "", &map[string]string{
+		"_max_results":      "1000",
+		"_paging":           "1",
+		"_return_as_object": "1",
+		"view":              "",
+		"zone":              "0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa"})
+}
+
 func TestInfobloxApplyChanges(t *testing.T) {
 	client := mockIBConnector{}
 
@@ -843,12 +1679,21 @@ func TestInfobloxApplyChanges(t *testing.T) {
 		endpoint.NewEndpoint("newcname.example.com", endpoint.RecordTypeCNAME, "other.com"),
 		endpoint.NewEndpoint("multiple.example.com", endpoint.RecordTypeA, "1.2.3.4,3.4.5.6,8.9.10.11"),
 		endpoint.NewEndpoint("multiple.example.com", endpoint.RecordTypeTXT, "tag-multiple-A-records"),
+		endpoint.NewEndpoint("dualstack.example.com", endpoint.RecordTypeA, "4.3.2.1"),
+		endpoint.NewEndpoint("dualstack.example.com", endpoint.RecordTypeAAAA, "2001:db8::5"),
+		endpoint.NewEndpoint("mail.example.com", endpoint.RecordTypeMX, "10 mx1.example.com"),
+		endpoint.NewEndpoint("_sip._tcp.example.com", endpoint.RecordTypeSRV, "10 60 5060 sip.example.com"),
+		endpoint.NewEndpoint("example.com", recordTypeCAA, `0 issue "letsencrypt.org"`),
 	})
 
 	validateEndpoints(t, client.deletedEndpoints, []*endpoint.Endpoint{
 		endpoint.NewEndpoint("old.example.com", endpoint.RecordTypeA, ""),
 		endpoint.NewEndpoint("oldcname.example.com", endpoint.RecordTypeCNAME, ""),
 		endpoint.NewEndpoint("deleted.example.com", endpoint.RecordTypeA, ""),
+		endpoint.NewEndpoint("deleted.example.com", endpoint.RecordTypeAAAA, ""),
+		endpoint.NewEndpoint("deleted.example.com", endpoint.RecordTypeMX, ""),
+		endpoint.NewEndpoint("_deleted._tcp.example.com", endpoint.RecordTypeSRV, ""),
+		endpoint.NewEndpoint("deleted.example.com", recordTypeCAA, ""),
 		endpoint.NewEndpoint("deletedcname.example.com", endpoint.RecordTypeCNAME, ""),
 	})
 
@@ -875,12 +1720,23 @@ func TestInfobloxApplyChangesReverse(t *testing.T) {
 		endpoint.NewEndpoint("newcname.example.com", endpoint.RecordTypeCNAME, "other.com"),
 		endpoint.NewEndpoint("multiple.example.com", endpoint.RecordTypeA, "1.2.3.4,3.4.5.6,8.9.10.11"),
 		endpoint.NewEndpoint("multiple.example.com", endpoint.RecordTypeTXT, "tag-multiple-A-records"),
+		endpoint.NewEndpoint("dualstack.example.com", endpoint.RecordTypeA, "4.3.2.1"),
+		endpoint.NewEndpoint("dualstack.example.com", endpoint.RecordTypeAAAA, "2001:db8::5"),
+		endpoint.NewEndpoint("dualstack.example.com", endpoint.RecordTypePTR, "2001:db8::5"),
+		endpoint.NewEndpoint("mail.example.com", endpoint.RecordTypeMX, "10 mx1.example.com"),
+		endpoint.NewEndpoint("_sip._tcp.example.com", endpoint.RecordTypeSRV, "10 60 5060 sip.example.com"),
+		endpoint.NewEndpoint("example.com", recordTypeCAA, `0 issue "letsencrypt.org"`),
 	})
 
 	validateEndpoints(t, client.deletedEndpoints, []*endpoint.Endpoint{
 		endpoint.NewEndpoint("old.example.com", endpoint.RecordTypeA, ""),
 		endpoint.NewEndpoint("oldcname.example.com", endpoint.RecordTypeCNAME, ""),
 		endpoint.NewEndpoint("deleted.example.com", endpoint.RecordTypeA, ""),
+		endpoint.NewEndpoint("deleted.example.com", endpoint.RecordTypeAAAA, ""),
+		endpoint.NewEndpoint("deleted.example.com", endpoint.RecordTypeMX, ""),
+		endpoint.NewEndpoint("_deleted._tcp.example.com", endpoint.RecordTypeSRV, ""),
+		endpoint.NewEndpoint("deleted.example.com", recordTypeCAA, ""),
+		endpoint.NewEndpoint("deleted.example.com", endpoint.RecordTypePTR, ""),
 		endpoint.NewEndpoint("deleted.example.com", endpoint.RecordTypePTR, ""),
 		endpoint.NewEndpoint("deletedcname.example.com", endpoint.RecordTypeCNAME, ""),
 	})
@@ -888,6 +1744,335 @@ func TestInfobloxApplyChangesReverse(t *testing.T) {
 	validateEndpoints(t, client.updatedEndpoints, []*endpoint.Endpoint{})
 }
 
+func TestInfobloxApplyChangesHostMode(t *testing.T) {
+	multi := createMockInfobloxObjectWithZone("multi.example.com", "HOST", "1.2.3.20", "example.com").(*ibclient.HostRecord)
+	multi.Ipv4Addrs = append(multi.Ipv4Addrs, *ibclient.NewHostRecordIpv4Addr("1.2.3.21", "", false, ""))
+	client := mockIBConnector{
+		mockInfobloxZones: &[]ibclient.ZoneAuth{
+			createMockInfobloxZone("example.com"),
+			createMockInfobloxZone("1.2.3.0/24"),
+		},
+		mockInfobloxObjects: &[]ibclient.IBObject{
+			createMockInfobloxObjectWithZone("existing.example.com", "HOST", "1.2.3.10", "example.com"),
+			createMockInfobloxObjectWithZone("gone.example.com", "HOST", "1.2.3.30", "example.com"),
+			multi,
+		},
+	}
+
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com", "1.2.3.0/24"}), provider.NewZoneIDFilter([]string{""}), "", false, true, &client)
+	providerCfg.config.RecordMode = recordModeHost
+
+	changes := &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("new.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+			endpoint.NewEndpoint("new.example.com", endpoint.RecordTypeAAAA, "2001:db8::4"),
+			endpoint.NewEndpoint("existing.example.com", endpoint.RecordTypeA, "1.2.3.11"),
+		},
+		Delete: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("multi.example.com", endpoint.RecordTypeA, "1.2.3.20"),
+			endpoint.NewEndpoint("gone.example.com", endpoint.RecordTypeA, "1.2.3.30"),
+		},
+	}
+	adjusted, err := providerCfg.AdjustEndpoints(changes.Create)
+	assert.NoError(t, err)
+	for _, ep := range adjusted {
+		assert.Empty(t, ep.ProviderSpecific, "PTR records of hosts are left to Infoblox")
+	}
+	assert.NoError(t, providerCfg.ApplyChanges(context.Background(), changes))
+
+	// the first address creates the host, the second one is added to it
+	assert.Len(t, client.createdObjects, 1)
+	created := client.createdObjects[0].(*ibclient.HostRecord)
+	assert.Equal(t, "new.example.com", AsString(created.Name))
+	assert.Equal(t, "", AsString(created.View))
+	assert.True(t, *created.EnableDns)
+	assert.ElementsMatch(t, []string{"1.2.3.4", "2001:db8::4"}, hostRecordAddresses(created))
+
+	hosts := map[string]*ibclient.HostRecord{}
+	for _, object := range *client.mockInfobloxObjects {
+		if host, ok := object.(*ibclient.HostRecord); ok {
+			hosts[AsString(host.Name)] = host
+		}
+	}
+	assert.ElementsMatch(t, []string{"1.2.3.10", "1.2.3.11"}, hostRecordAddresses(hosts["existing.example.com"]))
+	assert.ElementsMatch(t, []string{"1.2.3.21"}, hostRecordAddresses(hosts["multi.example.com"]))
+
+	// only the host without any remaining address is deleted
+	validateEndpoints(t, client.deletedEndpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("gone.example.com", endpoint.RecordTypeA, ""),
+	})
+}
+
+func TestInfobloxApplyChangesHostRecordOrigin(t *testing.T) {
+	multi := createMockInfobloxObjectWithZone("multi.example.com", "HOST", "1.2.3.20", "example.com").(*ibclient.HostRecord)
+	multi.Ipv4Addrs = append(multi.Ipv4Addrs, *ibclient.NewHostRecordIpv4Addr("1.2.3.21", "", false, ""))
+	client := mockIBConnector{
+		mockInfobloxZones: &[]ibclient.ZoneAuth{
+			createMockInfobloxZone("example.com"),
+		},
+		mockInfobloxObjects: &[]ibclient.IBObject{
+			multi,
+			createMockInfobloxObjectWithZone("plain.example.com", endpoint.RecordTypeA, "1.2.3.40", "example.com"),
+		},
+	}
+
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, &client)
+
+	records, err := providerCfg.Records(context.Background())
+	assert.NoError(t, err)
+	current := map[string]*endpoint.Endpoint{}
+	for _, ep := range records {
+		current[ep.DNSName] = ep
+	}
+	desired, err := providerCfg.AdjustEndpoints([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("multi.example.com", endpoint.RecordTypeA, "1.2.3.21", "1.2.3.22"),
+		endpoint.NewEndpoint("plain.example.com", endpoint.RecordTypeA, "1.2.3.41"),
+	})
+	assert.NoError(t, err)
+
+	// desired endpoints of Host records carry the same marker as the current ones
+	for _, ep := range []*endpoint.Endpoint{current["multi.example.com"], desired[0]} {
+		value, ok := ep.GetProviderSpecificProperty(providerSpecificInfobloxHostRecord)
+		assert.True(t, ok)
+		assert.Equal(t, "true", value)
+	}
+	for _, ep := range []*endpoint.Endpoint{current["plain.example.com"], desired[1]} {
+		_, ok := ep.GetProviderSpecificProperty(providerSpecificInfobloxHostRecord)
+		assert.False(t, ok)
+	}
+
+	changes := &plan.Changes{
+		UpdateOld: []*endpoint.Endpoint{current["multi.example.com"]},
+		UpdateNew: []*endpoint.Endpoint{desired[0]},
+	}
+	assert.NoError(t, providerCfg.ApplyChanges(context.Background(), changes))
+
+	// the address is swapped within the Host record, which is neither deleted nor duplicated as A record
+	assert.ElementsMatch(t, []string{"1.2.3.21", "1.2.3.22"}, hostRecordAddresses(multi))
+	assert.Empty(t, client.createdEndpoints)
+	assert.Empty(t, client.deletedEndpoints)
+}
+
+func TestInfobloxSyncHostRecordPTR(t *testing.T) {
+	client := mockIBConnector{
+		mockInfobloxZones: &[]ibclient.ZoneAuth{
+			createMockInfobloxZone("example.com"),
+			createMockInfobloxZone("1.2.3.0/24"),
+		},
+		mockInfobloxObjects: &[]ibclient.IBObject{
+			createMockInfobloxObjectWithZone("host.example.com", "HOST", "1.2.3.10", "example.com"),
+		},
+	}
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com", "1.2.3.0/24"}), provider.NewZoneIDFilter([]string{""}), "", false, true, &client)
+
+	// the mock keeps the TTL of the Host record, so each cycle updates it
+	for cycle := 0; cycle < 2; cycle++ {
+		current, err := providerCfg.Records(context.Background())
+		require.NoError(t, err)
+		desired, err := providerCfg.AdjustEndpoints([]*endpoint.Endpoint{
+			endpoint.NewEndpointWithTTL("host.example.com", endpoint.RecordTypeA, 600, "1.2.3.10"),
+		})
+		require.NoError(t, err)
+		changes := (&plan.Plan{
+			Current:        current,
+			Desired:        desired,
+			Policies:       []plan.Policy{&plan.SyncPolicy{}},
+			ManagedRecords: []string{endpoint.RecordTypeA, endpoint.RecordTypePTR},
+		}).Calculate().Changes
+		require.NoError(t, providerCfg.ApplyChanges(context.Background(), changes))
+	}
+
+	// the reverse mapping of the Host record is left to Infoblox
+	for _, obj := range client.createdObjects {
+		assert.NotEqual(t, ibclient.NewEmptyRecordPTR().ObjectType(), obj.ObjectType())
+	}
+	validateEndpoints(t, client.createdEndpoints, []*endpoint.Endpoint{})
+}
+
+func TestInfobloxAdjustEndpointsHostRecordType(t *testing.T) {
+	client := mockIBConnector{
+		mockInfobloxZones: &[]ibclient.ZoneAuth{
+			createMockInfobloxZone("example.com"),
+		},
+		mockInfobloxObjects: &[]ibclient.IBObject{
+			createMockInfobloxObjectWithZone("dual.example.com", "HOST", "1.2.3.30", "example.com"),
+			createMockInfobloxObjectWithZone("dual.example.com", endpoint.RecordTypeAAAA, "2001:db8::30", "example.com"),
+		},
+	}
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, &client)
+
+	_, err := providerCfg.Records(context.Background())
+	assert.NoError(t, err)
+	desired, err := providerCfg.AdjustEndpoints([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("dual.example.com", endpoint.RecordTypeA, "1.2.3.30"),
+		endpoint.NewEndpoint("dual.example.com", endpoint.RecordTypeAAAA, "2001:db8::30"),
+	})
+	assert.NoError(t, err)
+
+	// only the A endpoint comes from the Host record, the AAAA endpoint is a record:aaaa
+	value, ok := desired[0].GetProviderSpecificProperty(providerSpecificInfobloxHostRecord)
+	assert.True(t, ok)
+	assert.Equal(t, "true", value)
+	_, ok = desired[1].GetProviderSpecificProperty(providerSpecificInfobloxHostRecord)
+	assert.False(t, ok)
+}
+
+func TestHostRecordAction(t *testing.T) {
+	empty := &ibclient.HostRecord{}
+	single := &ibclient.HostRecord{Ipv4Addrs: []ibclient.HostRecordIpv4Addr{*ibclient.NewHostRecordIpv4Addr("1.2.3.4", "", false, "")}}
+	ref := "record:host/ZG5z:host.example.com/default"
+
+	assert.Equal(t, infobloxCreate, hostRecordAction(infobloxCreate, "", single))
+	assert.Equal(t, infobloxUpdate, hostRecordAction(infobloxCreate, ref, single))
+	assert.Equal(t, infobloxUpdate, hostRecordAction(infobloxUpdate, ref, single))
+	assert.Equal(t, infobloxUpdate, hostRecordAction(infobloxDelete, ref, single))
+	assert.Equal(t, infobloxDelete, hostRecordAction(infobloxDelete, ref, empty))
+	assert.Equal(t, "", hostRecordAction(infobloxDelete, "", empty))
+	assert.Equal(t, infobloxCreate, hostRecordAction(infobloxUpdate, "", single))
+}
+
+func TestInfobloxApplyChangesHostModeUpdateMissing(t *testing.T) {
+	client := mockIBConnector{
+		mockInfobloxZones: &[]ibclient.ZoneAuth{
+			createMockInfobloxZone("example.com"),
+		},
+		mockInfobloxObjects: &[]ibclient.IBObject{},
+	}
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, &client)
+	providerCfg.config.RecordMode = recordModeHost
+
+	// the host has been deleted outside of external-dns, the update creates it again
+	changes := &plan.Changes{
+		UpdateOld: []*endpoint.Endpoint{endpoint.NewEndpointWithTTL("gone.example.com", endpoint.RecordTypeA, 300, "1.2.3.4")},
+		UpdateNew: []*endpoint.Endpoint{endpoint.NewEndpointWithTTL("gone.example.com", endpoint.RecordTypeA, 600, "1.2.3.4")},
+	}
+	require.NoError(t, providerCfg.ApplyChanges(context.Background(), changes))
+	require.Len(t, client.createdObjects, 1)
+	created := client.createdObjects[0].(*ibclient.HostRecord)
+	assert.Equal(t, "gone.example.com", AsString(created.Name))
+	assert.Equal(t, int64(600), AsInt64(created.Ttl))
+	assert.Equal(t, []string{"1.2.3.4"}, hostRecordAddresses(created))
+}
+
+func TestInfobloxApplyChangesTTLDrift(t *testing.T) {
+	withTTL := func(obj ibclient.IBObject, ttl uint32, ea ibclient.EA) ibclient.IBObject {
+		useTTL := true
+		switch record := obj.(type) {
+		case *ibclient.RecordA:
+			record.Ttl, record.UseTtl, record.Ea = &ttl, &useTTL, ea
+		case *ibclient.RecordTXT:
+			record.Ttl, record.UseTtl, record.Ea = &ttl, &useTTL, ea
+		}
+		return obj
+	}
+	owner := ibclient.EA{"owner": "team-a"}
+	client := mockIBConnector{
+		mockInfobloxZones: &[]ibclient.ZoneAuth{
+			createMockInfobloxZone("example.com"),
+		},
+		mockInfobloxObjects: &[]ibclient.IBObject{
+			withTTL(createMockInfobloxObjectWithZone("multi.example.com", endpoint.RecordTypeA, "1.1.1.1", "example.com"), 300, owner),
+			withTTL(createMockInfobloxObjectWithZone("multi.example.com", endpoint.RecordTypeA, "2.2.2.2", "example.com"), 600, owner),
+			withTTL(createMockInfobloxObjectWithZone("multi.example.com", endpoint.RecordTypeA, "3.3.3.3", "example.com"), 600, ibclient.EA{"owner": "team-b"}),
+			withTTL(createMockInfobloxObjectWithZone("multi.example.com", endpoint.RecordTypeTXT, "tag", "example.com"), 600, owner),
+		},
+	}
+
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, &client)
+	providerCfg.config.ExtAttrsJSON = `{"owner": "team-a"}`
+
+	changes := &plan.Changes{
+		UpdateOld: []*endpoint.Endpoint{
+			endpoint.NewEndpointWithTTL("multi.example.com", endpoint.RecordTypeA, 300, "1.1.1.1", "2.2.2.2", "3.3.3.3", "4.4.4.4"),
+			endpoint.NewEndpointWithTTL("multi.example.com", endpoint.RecordTypeTXT, 300, "tag"),
+		},
+		UpdateNew: []*endpoint.Endpoint{
+			endpoint.NewEndpointWithTTL("multi.example.com", endpoint.RecordTypeA, 600, "1.1.1.1", "2.2.2.2", "3.3.3.3", "4.4.4.4"),
+			endpoint.NewEndpointWithTTL("multi.example.com", endpoint.RecordTypeTXT, 600, "tag"),
+		},
+	}
+	assert.NoError(t, providerCfg.ApplyChanges(context.Background(), changes))
+
+	// 1.1.1.1 has a different TTL, 3.3.3.3 different extensible attributes, 2.2.2.2 and the TXT record are up to date
+	assert.ElementsMatch(t, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("multi.example.com", endpoint.RecordTypeA, "1.1.1.1"),
+		endpoint.NewEndpoint("multi.example.com", endpoint.RecordTypeA, "3.3.3.3"),
+	}, client.updatedEndpoints)
+	// 4.4.4.4 is missing in Infoblox and gets created
+	assert.ElementsMatch(t, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("multi.example.com", endpoint.RecordTypeA, "4.4.4.4"),
+	}, client.createdEndpoints)
+	assert.Empty(t, client.deletedEndpoints)
+}
+
+func TestInfobloxApplyChangesDuplicateCreate(t *testing.T) {
+	withTTL := func(obj ibclient.IBObject, ttl uint32) ibclient.IBObject {
+		useTTL := true
+		switch record := obj.(type) {
+		case *ibclient.RecordA:
+			record.Ttl, record.UseTtl = &ttl, &useTTL
+		case *ibclient.RecordCNAME:
+			record.Ttl, record.UseTtl = &ttl, &useTTL
+		}
+		return obj
+	}
+	newClient := func() *mockIBConnector {
+		return &mockIBConnector{
+			mockInfobloxZones: &[]ibclient.ZoneAuth{
+				createMockInfobloxZone("example.com"),
+			},
+			mockInfobloxObjects: &[]ibclient.IBObject{
+				withTTL(createMockInfobloxObjectWithZone("same.example.com", endpoint.RecordTypeA, "1.1.1.1", "example.com"), 300),
+				withTTL(createMockInfobloxObjectWithZone("drift.example.com", endpoint.RecordTypeA, "2.2.2.2", "example.com"), 300),
+				withTTL(createMockInfobloxObjectWithZone("alias.example.com", endpoint.RecordTypeCNAME, "other.com", "example.com"), 300),
+			},
+			rejectDuplicates: true,
+		}
+	}
+
+	t.Run("existing records are kept or updated", func(t *testing.T) {
+		client := newClient()
+		providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, client)
+
+		changes := &plan.Changes{
+			Create: []*endpoint.Endpoint{
+				endpoint.NewEndpointWithTTL("same.example.com", endpoint.RecordTypeA, 300, "1.1.1.1"),
+				endpoint.NewEndpointWithTTL("drift.example.com", endpoint.RecordTypeA, 600, "2.2.2.2"),
+				endpoint.NewEndpointWithTTL("alias.example.com", endpoint.RecordTypeCNAME, 300, "other.com"),
+				endpoint.NewEndpointWithTTL("new.example.com", endpoint.RecordTypeA, 300, "3.3.3.3"),
+			},
+		}
+		assert.NoError(t, providerCfg.ApplyChanges(context.Background(), changes))
+
+		assert.ElementsMatch(t, []*endpoint.Endpoint{
+			endpoint.NewEndpoint("new.example.com", endpoint.RecordTypeA, "3.3.3.3"),
+		}, client.createdEndpoints)
+		// only the record with the different TTL is updated
+		assert.ElementsMatch(t, []*endpoint.Endpoint{
+			endpoint.NewEndpoint("drift.example.com", endpoint.RecordTypeA, "2.2.2.2"),
+		}, client.updatedEndpoints)
+		assert.Empty(t, client.deletedEndpoints)
+	})
+
+	t.Run("conflict with a different record", func(t *testing.T) {
+		client := newClient()
+		providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, client)
+
+		changes := &plan.Changes{
+			Create: []*endpoint.Endpoint{
+				endpoint.NewEndpointWithTTL("alias.example.com", endpoint.RecordTypeCNAME, 300, "another.com"),
+			},
+		}
+		err := providerCfg.ApplyChanges(context.Background(), changes)
+
+		// the CNAME record of the name points elsewhere, it is not overwritten
+		var changeErr *ChangeError
+		assert.True(t, errors.As(err, &changeErr))
+		assert.Equal(t, wapiConflictCode, changeErr.WAPICode)
+		assert.Empty(t, client.updatedEndpoints)
+	})
+}
+
 func TestInfobloxApplyChangesDryRun(t *testing.T) {
 	client := mockIBConnector{
 		mockInfobloxObjects: &[]ibclient.IBObject{},
@@ -907,11 +2092,17 @@ func testInfobloxApplyChangesInternal(t *testing.T, dryRun, createPTR bool, clie
 		createMockInfobloxZone("example.com"),
 		createMockInfobloxZone("other.com"),
 		createMockInfobloxZone("1.2.3.0/24"),
+		createMockInfobloxZone("2001:db8::/48"),
 	}
 	client.(*mockIBConnector).mockInfobloxObjects = &[]ibclient.IBObject{
 		createMockInfobloxObjectWithZone("deleted.example.com", endpoint.RecordTypeA, "121.212.121.212", "example.com"),
+		createMockInfobloxObjectWithZone("deleted.example.com", endpoint.RecordTypeAAAA, "2001:db8::121", "example.com"),
+		createMockInfobloxObjectWithZone("deleted.example.com", endpoint.RecordTypeMX, "10 mx.example.com", "example.com"),
+		createMockInfobloxObjectWithZone("_deleted._tcp.example.com", endpoint.RecordTypeSRV, "10 60 5060 sip.example.com", "example.com"),
+		createMockInfobloxObjectWithZone("deleted.example.com", recordTypeCAA, `0 issue "letsencrypt.org"`, "example.com"),
 		createMockInfobloxObjectWithZone("deleted.example.com", endpoint.RecordTypeTXT, "test-deleting-txt", "example.com"),
 		createMockInfobloxObjectWithZone("deleted.example.com", endpoint.RecordTypePTR, "121.212.121.212", "example.com"),
+		createMockInfobloxObjectWithZone("deleted.example.com", endpoint.RecordTypePTR, "2001:db8::121", "0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa"),
 		createMockInfobloxObjectWithZone("deletedcname.example.com", endpoint.RecordTypeCNAME, "other.com", "example.com"),
 		createMockInfobloxObjectWithZone("old.example.com", endpoint.RecordTypeA, "121.212.121.212", "example.com"),
 		createMockInfobloxObjectWithZone("oldcname.example.com", endpoint.RecordTypeCNAME, "other.com", "example.com"),
@@ -939,6 +2130,11 @@ func testInfobloxApplyChangesInternal(t *testing.T, dryRun, createPTR bool, clie
 		endpoint.NewEndpoint("nope.com", endpoint.RecordTypeTXT, "tag"),
 		endpoint.NewEndpoint("multiple.example.com", endpoint.RecordTypeA, "1.2.3.4,3.4.5.6,8.9.10.11"),
 		endpoint.NewEndpoint("multiple.example.com", endpoint.RecordTypeTXT, "tag-multiple-A-records"),
+		endpoint.NewEndpoint("dualstack.example.com", endpoint.RecordTypeA, "4.3.2.1"),
+		endpoint.NewEndpoint("dualstack.example.com", endpoint.RecordTypeAAAA, "2001:db8::5"),
+		endpoint.NewEndpoint("mail.example.com", endpoint.RecordTypeMX, "10 mx1.example.com"),
+		endpoint.NewEndpoint("_sip._tcp.example.com", endpoint.RecordTypeSRV, "10 60 5060 sip.example.com"),
+		endpoint.NewEndpoint("example.com", recordTypeCAA, `0 issue "letsencrypt.org"`),
 	}
 
 	updateOldRecords := []*endpoint.Endpoint{
@@ -955,6 +2151,10 @@ func testInfobloxApplyChangesInternal(t *testing.T, dryRun, createPTR bool, clie
 
 	deleteRecords := []*endpoint.Endpoint{
 		endpoint.NewEndpoint("deleted.example.com", endpoint.RecordTypeA, "121.212.121.212"),
+		endpoint.NewEndpoint("deleted.example.com", endpoint.RecordTypeAAAA, "2001:db8::121"),
+		endpoint.NewEndpoint("deleted.example.com", endpoint.RecordTypeMX, "10 mx.example.com"),
+		endpoint.NewEndpoint("_deleted._tcp.example.com", endpoint.RecordTypeSRV, "10 60 5060 sip.example.com"),
+		endpoint.NewEndpoint("deleted.example.com", recordTypeCAA, `0 issue "letsencrypt.org"`),
 		endpoint.NewEndpoint("deletedcname.example.com", endpoint.RecordTypeCNAME, "other.com"),
 		endpoint.NewEndpoint("deleted.nope.com", endpoint.RecordTypeA, "222.111.222.111"),
 	}
@@ -987,7 +2187,7 @@ func TestInfobloxZones(t *testing.T) {
 	}
 
 	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com", "1.2.3.0/24"}), provider.NewZoneIDFilter([]string{""}), "", true, false, &client)
-	zoneAuths, _ := providerCfg.zones()
+	zoneAuths, _ := providerCfg.zones(context.Background())
 	zones := zonePointerConverter(zoneAuths)
 	var emptyZoneAuth *ibclient.ZoneAuth
 	assert.Equal(t, providerCfg.findZone(zones, "example.com").Fqdn, "example.com")
@@ -1006,19 +2206,26 @@ func TestInfobloxReverseZones(t *testing.T) {
 		mockInfobloxZones: &[]ibclient.ZoneAuth{
 			createMockInfobloxZone("example.com"),
 			createMockInfobloxZone("1.2.3.0/24"),
+			createMockInfobloxZone("10.1.0.0/16"),
 			createMockInfobloxZone("10.0.0.0/8"),
+			createMockInfobloxZone("2001:db8:1::/48"),
+			createMockInfobloxZone("2001:db8::/32"),
 		},
 		mockInfobloxObjects: &[]ibclient.IBObject{},
 	}
 
-	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com", "1.2.3.0/24", "10.0.0.0/8"}), provider.NewZoneIDFilter([]string{""}), "", true, false, &client)
-	zoneAuths, _ := providerCfg.zones()
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com", "1.2.3.0/24", "10.1.0.0/16", "10.0.0.0/8", "2001:db8:1::/48", "2001:db8::/32"}), provider.NewZoneIDFilter([]string{""}), "", true, false, &client)
+	zoneAuths, _ := providerCfg.zones(context.Background())
 	zones := zonePointerConverter(zoneAuths)
 	var emptyZoneAuth *ibclient.ZoneAuth
 	assert.Equal(t, providerCfg.findReverseZone(zones, "nomatch-example.com"), emptyZoneAuth)
 	assert.Equal(t, providerCfg.findReverseZone(zones, "192.168.0.1"), emptyZoneAuth)
 	assert.Equal(t, providerCfg.findReverseZone(zones, "1.2.3.4").Fqdn, "1.2.3.0/24")
 	assert.Equal(t, providerCfg.findReverseZone(zones, "10.28.29.30").Fqdn, "10.0.0.0/8")
+	assert.Equal(t, providerCfg.findReverseZone(zones, "10.1.29.30").Fqdn, "10.1.0.0/16")
+	assert.Equal(t, providerCfg.findReverseZone(zones, "2001:db8:1::5").Fqdn, "2001:db8:1::/48")
+	assert.Equal(t, providerCfg.findReverseZone(zones, "2001:db8:2::5").Fqdn, "2001:db8::/32")
+	assert.Equal(t, providerCfg.findReverseZone(zones, "2001:db9::5"), emptyZoneAuth)
 }
 
 func TestExtendedRequestFDQDRegExBuilder(t *testing.T) {
@@ -1071,6 +2278,12 @@ func TestExtendedRequestNameRegExBuilder(t *testing.T) {
 	req, _ = requestBuilder.BuildRequest(ibclient.CREATE, obj, "", &ibclient.QueryParams{})
 
 	assert.True(t, req.URL.Query().Get("name~") == "")
+	// objects without a name are not filtered by it
+	for _, unnamed := range []ibclient.IBObject{ibclient.NewGrid(ibclient.Grid{}), &ibclient.DbObjects{}} {
+		req, _ = requestBuilder.BuildRequest(ibclient.GET, unnamed, "", &ibclient.QueryParams{})
+
+		assert.True(t, req.URL.Query().Get("name~") == "")
+	}
 }
 
 func TestExtendedRequestMaxResultsBuilder(t *testing.T) {
@@ -1095,6 +2308,11 @@ func TestExtendedRequestMaxResultsBuilder(t *testing.T) {
 
 	assert.True(t, req.URL.Query().Get("_max_results") == "54321")
 
+	// the page size of paged requests is kept
+	req, _ = requestBuilder.BuildRequest(ibclient.GET, obj, "", ibclient.NewQueryParams(false, map[string]string{"_paging": "1", "_max_results": "500"}))
+
+	assert.True(t, req.URL.Query().Get("_max_results") == "500")
+
 	req, _ = requestBuilder.BuildRequest(ibclient.CREATE, obj, "", &ibclient.QueryParams{})
 
 	assert.True(t, req.URL.Query().Get("_max_results") == "")
@@ -1139,3 +2357,82 @@ func (r *mockRequestor) SendRequest(req *http.Request) (res []byte, err error) {
 func validateEndpoints(t *testing.T, endpoints []*endpoint.Endpoint, expected []*endpoint.Endpoint) {
 	assert.True(t, SameEndpoints(endpoints, expected), "actual and expected endpoints don't match. %s:%s", endpoints, expected)
 }
+
+func TestInfobloxRecordsConcurrent(t *testing.T) {
+	newClient := func() *mockIBConnector {
+		return &mockIBConnector{
+			mockInfobloxZones: &[]ibclient.ZoneAuth{
+				createMockInfobloxZone("example.com"),
+				createMockInfobloxZone("other.com"),
+				createMockInfobloxZone("third.com"),
+			},
+			mockInfobloxObjects: &[]ibclient.IBObject{
+				createMockInfobloxObjectWithZone("a.example.com", endpoint.RecordTypeA, "1.1.1.1", "example.com"),
+				createMockInfobloxObjectWithZone("a.example.com", endpoint.RecordTypeTXT, "tag", "example.com"),
+				createMockInfobloxObjectWithZone("host.example.com", "HOST", "1.1.1.2", "example.com"),
+				createMockInfobloxObjectWithZone("a.other.com", endpoint.RecordTypeA, "2.2.2.2", "other.com"),
+				createMockInfobloxObjectWithZone("c.other.com", endpoint.RecordTypeCNAME, "a.other.com", "other.com"),
+				createMockInfobloxObjectWithZone("a.third.com", endpoint.RecordTypeAAAA, "2001:db8::1", "third.com"),
+				createMockInfobloxObjectWithZone("third.com", endpoint.RecordTypeMX, "10 mail.third.com", "third.com"),
+			},
+		}
+	}
+	records := func(concurrency int) []*endpoint.Endpoint {
+		providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com", "other.com", "third.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, newClient())
+		providerCfg.config.FetchConcurrency = concurrency
+		actual, err := providerCfg.Records(context.Background())
+		assert.NoError(t, err)
+		return actual
+	}
+
+	serial := records(1)
+	assert.Len(t, serial, 7)
+	// the endpoints are merged in the order of zones and record types, however many workers fetch them
+	for _, concurrency := range []int{2, 8, 64} {
+		assert.Equal(t, serial, records(concurrency))
+	}
+
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, newClient())
+	providerCfg.config.FetchConcurrency = 8
+	_, err := providerCfg.Records(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, providerCfg.hostRecords[hostRecordKey{name: "host.example.com", recordType: endpoint.RecordTypeA}])
+	assert.False(t, providerCfg.hostRecords[hostRecordKey{name: "host.example.com", recordType: endpoint.RecordTypeAAAA}])
+}
+
+func TestInfobloxFetchConcurrently(t *testing.T) {
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, &mockIBConnector{})
+	providerCfg.config.FetchConcurrency = 1
+
+	t.Run("the first error skips the remaining fetches", func(t *testing.T) {
+		var fetched []int
+		fetch := func(i int, err error) recordFetch {
+			return func(context.Context) ([]*endpoint.Endpoint, error) {
+				fetched = append(fetched, i)
+				return nil, err
+			}
+		}
+		_, err := providerCfg.fetchConcurrently(context.Background(), []recordFetch{
+			fetch(0, nil),
+			fetch(1, errors.New("could not fetch A records")),
+			fetch(2, nil),
+			fetch(3, nil),
+		})
+		assert.EqualError(t, err, "could not fetch A records")
+		assert.Equal(t, []int{0, 1}, fetched)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		fetched := false
+		_, err := providerCfg.fetchConcurrently(ctx, []recordFetch{
+			func(context.Context) ([]*endpoint.Endpoint, error) {
+				fetched = true
+				return nil, nil
+			},
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, fetched)
+	})
+}
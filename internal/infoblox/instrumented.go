@@ -0,0 +1,104 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+)
+
+// multiRequestObjectType is the object type of multi-requests in the WAPI metrics
+const multiRequestObjectType = "request"
+
+// instrumentedConnector counts the WAPI requests sent through the wrapped connector and
+// observes their latency, by object type and method. It wraps the WAPI client itself, so every
+// retry is counted and the wait for the rate limiter is not part of the latency.
+type instrumentedConnector struct {
+	ibclient.IBConnector
+}
+
+func newInstrumentedConnector(connector ibclient.IBConnector) *instrumentedConnector {
+	return &instrumentedConnector{IBConnector: connector}
+}
+
+func (c *instrumentedConnector) WithContext(ctx context.Context) ibclient.IBConnector {
+	return &instrumentedConnector{IBConnector: connectorWithContext(ctx, c.IBConnector)}
+}
+
+// observeWAPIRequest counts the request of the method on the object type and observes its latency
+func observeWAPIRequest(objectType, method string, start time.Time, err error) {
+	result := "success"
+	switch {
+	case isNotFoundError(err):
+		result = "not_found"
+	case err != nil:
+		result = "error"
+	}
+	wapiRequests.WithLabelValues(objectType, method, result).Inc()
+	wapiRequestDuration.WithLabelValues(objectType, method).Observe(time.Since(start).Seconds())
+}
+
+// refObjectType returns the object type of the reference, e.g. record:a for
+// record:a/ZG5zLmJpbmRfYSQuX2RlZmF1bHQuY29tLmV4YW1wbGUsYSwxLjEuMS4x:a.example.com/default
+func refObjectType(ref string) string {
+	objectType, _, _ := strings.Cut(ref, "/")
+	return objectType
+}
+
+func (c *instrumentedConnector) CreateObject(obj ibclient.IBObject) (string, error) {
+	start := time.Now()
+	ref, err := c.IBConnector.CreateObject(obj)
+	observeWAPIRequest(obj.ObjectType(), "create", start, err)
+	return ref, err
+}
+
+func (c *instrumentedConnector) GetObject(obj ibclient.IBObject, ref string, queryParams *ibclient.QueryParams, res interface{}) error {
+	start := time.Now()
+	err := c.IBConnector.GetObject(obj, ref, queryParams, res)
+	observeWAPIRequest(obj.ObjectType(), "get", start, err)
+	return err
+}
+
+func (c *instrumentedConnector) DeleteObject(ref string) (string, error) {
+	start := time.Now()
+	deleted, err := c.IBConnector.DeleteObject(ref)
+	observeWAPIRequest(refObjectType(ref), "delete", start, err)
+	return deleted, err
+}
+
+func (c *instrumentedConnector) UpdateObject(obj ibclient.IBObject, ref string) (string, error) {
+	start := time.Now()
+	updated, err := c.IBConnector.UpdateObject(obj, ref)
+	observeWAPIRequest(obj.ObjectType(), "update", start, err)
+	return updated, err
+}
+
+func (c *instrumentedConnector) SendMultiRequest(req *ibclient.MultiRequest) ([]map[string]interface{}, error) {
+	multi, ok := c.IBConnector.(multiRequestConnector)
+	if !ok {
+		return nil, errMultiRequestUnsupported
+	}
+	start := time.Now()
+	res, err := multi.SendMultiRequest(req)
+	observeWAPIRequest(multiRequestObjectType, "multi", start, err)
+	return res, err
+}
@@ -0,0 +1,185 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+// journalEntry is the inverse of a change applied to Infoblox
+type journalEntry struct {
+	zone   string
+	change *infobloxChange
+	// action undoing the change, on the object holding the prior state or on the reference
+	action string
+	obj    ibclient.IBObject
+	ref    string
+}
+
+// journal records the inverse of the changes applied in transactional mode, so they can
+// be rolled back when a later change fails
+type journal struct {
+	entries []journalEntry
+}
+
+// add records the inverse of the action which has been applied for the change. The prior
+// state of the changed object is taken from the record set fetched before applying it.
+func (j *journal) add(zone string, change *infobloxChange, action string, record *infobloxRecordSet, ref string, view string) {
+	entry := journalEntry{zone: zone, change: change, ref: ref}
+	switch action {
+	case infobloxCreate:
+		entry.action = infobloxDelete
+	case infobloxDelete:
+		entry.action = infobloxCreate
+		entry.obj = priorObject(record, view)
+	case infobloxUpdate:
+		entry.action = infobloxUpdate
+		entry.obj = priorObject(record, "")
+	}
+	j.entries = append(j.entries, entry)
+}
+
+// priorObject returns a copy of the desired object with the TTL, extensible attributes
+// and addresses of the existing object. The view is only set for objects to be created.
+func priorObject(record *infobloxRecordSet, view string) ibclient.IBObject {
+	existing := reflect.ValueOf(record.res).Elem().Index(0)
+	prior := reflect.New(reflect.TypeOf(record.obj).Elem())
+	prior.Elem().Set(reflect.ValueOf(record.obj).Elem())
+	for _, name := range []string{"Ttl", "UseTtl", "Ea", "Addresses"} {
+		if field := prior.Elem().FieldByName(name); field.IsValid() {
+			field.Set(existing.FieldByName(name))
+		}
+	}
+	if field := prior.Elem().FieldByName("View"); field.IsValid() && view != "" {
+		if field.Kind() == reflect.Ptr {
+			field.Set(reflect.ValueOf(&view))
+		} else {
+			field.SetString(view)
+		}
+	}
+	if host, ok := prior.Interface().(*ibclient.HostRecord); ok {
+		host.Ipv4Addrs, host.Ipv6Addrs = writableHostAddresses(existing.Addr().Interface().(*ibclient.HostRecord), "")
+	}
+	return prior.Interface().(ibclient.IBObject)
+}
+
+// TransactionError is returned in transactional mode when a change failed. It reports the
+// outcome of rolling back the changes which had been applied before.
+type TransactionError struct {
+	Err            error
+	Applied        int
+	RolledBack     int
+	RollbackErrors []error
+}
+
+func (e *TransactionError) Error() string {
+	msg := fmt.Sprintf("%s; rolled back %d of %d applied changes", e.Err, e.RolledBack, e.Applied)
+	if len(e.RollbackErrors) > 0 {
+		errs := make([]string, 0, len(e.RollbackErrors))
+		for _, err := range e.RollbackErrors {
+			errs = append(errs, err.Error())
+		}
+		msg += fmt.Sprintf(", rollback failed: %s", strings.Join(errs, "; "))
+	}
+	return msg
+}
+
+func (e *TransactionError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON reports the failed change and the outcome of the rollback as JSON
+func (e *TransactionError) MarshalJSON() ([]byte, error) {
+	report := struct {
+		Message        string              `json:"message"`
+		Failed         *changeErrorReport  `json:"failed,omitempty"`
+		Error          string              `json:"error,omitempty"`
+		Applied        int                 `json:"applied"`
+		RolledBack     int                 `json:"rolledBack"`
+		RollbackFailed []changeErrorReport `json:"rollbackFailed,omitempty"`
+	}{
+		Message:    fmt.Sprintf("rolled back %d of %d applied changes", e.RolledBack, e.Applied),
+		Applied:    e.Applied,
+		RolledBack: e.RolledBack,
+	}
+	var changeErr *ChangeError
+	if errors.As(e.Err, &changeErr) {
+		failed := changeErr.report()
+		report.Failed = &failed
+	} else {
+		report.Error = e.Err.Error()
+	}
+	for _, err := range e.RollbackErrors {
+		if errors.As(err, &changeErr) {
+			report.RollbackFailed = append(report.RollbackFailed, changeErr.report())
+		} else {
+			report.RollbackFailed = append(report.RollbackFailed, changeErrorReport{Error: err.Error()})
+		}
+	}
+	return json.Marshal(report)
+}
+
+// rollback undoes the journaled changes in reverse order. An undo which fails is reported,
+// the remaining ones are attempted nevertheless. The rollback is not aborted by the
+// cancellation of the context, which would leave the changes half applied.
+func (p *Provider) rollback(ctx context.Context, j *journal, cause error) *TransactionError {
+	txErr := &TransactionError{Err: cause, Applied: len(j.entries)}
+	client := p.clientWithContext(context.WithoutCancel(ctx))
+	for i := len(j.entries) - 1; i >= 0; i-- {
+		entry := j.entries[i]
+		logFields := log.Fields{
+			"action": entry.action,
+			"record": entry.change.Endpoint.DNSName,
+			"type":   entry.change.Endpoint.RecordType,
+			"target": entry.change.Endpoint.Targets,
+			"zone":   entry.zone,
+		}
+		var err error
+		switch entry.action {
+		case infobloxCreate:
+			_, err = client.CreateObject(entry.obj)
+		case infobloxDelete:
+			_, err = client.DeleteObject(entry.ref)
+		case infobloxUpdate:
+			_, err = client.UpdateObject(entry.obj, entry.ref)
+		}
+		if err != nil {
+			log.WithFields(logFields).Errorf("Could not roll back change: %s", err)
+			undo := &infobloxChange{Action: entry.action, Endpoint: entry.change.Endpoint}
+			txErr.RollbackErrors = append(txErr.RollbackErrors, newChangeError(entry.zone, undo, err))
+			continue
+		}
+		log.WithFields(logFields).Info("Rolled back change")
+		txErr.RolledBack++
+	}
+	if len(txErr.RollbackErrors) > 0 {
+		log.Errorf("Rollback incomplete: %s", txErr)
+	} else {
+		log.Warnf("Rolled back: %s", txErr)
+	}
+	return txErr
+}
@@ -0,0 +1,161 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/provider"
+)
+
+func TestInfobloxSubmitChangesRollback(t *testing.T) {
+	withTTL := func(obj ibclient.IBObject, ttl uint32) ibclient.IBObject {
+		useTTL := true
+		record := obj.(*ibclient.RecordA)
+		record.Ttl, record.UseTtl, record.Ea = &ttl, &useTTL, ibclient.EA{"owner": "team-a"}
+		return obj
+	}
+	newChanges := func() []*infobloxChange {
+		return []*infobloxChange{
+			{Action: infobloxCreate, Endpoint: endpoint.NewEndpoint("added.example.com", endpoint.RecordTypeA, "1.2.3.4")},
+			{Action: infobloxUpdate, Endpoint: endpoint.NewEndpointWithTTL("ttl.example.com", endpoint.RecordTypeA, 600, "2.2.2.2")},
+			{Action: infobloxDelete, Endpoint: endpoint.NewEndpoint("old.example.com", endpoint.RecordTypeA, "1.1.1.1")},
+			{Action: infobloxCreate, Endpoint: endpoint.NewEndpoint("fail.example.com", endpoint.RecordTypeA, "9.9.9.9")},
+		}
+	}
+	wapiErr := errors.New("WAPI request error: 400 Bad Request")
+
+	tests := []struct {
+		name             string
+		createErrors     map[string]error
+		rolledBack       int
+		rollbackErrors   int
+		expectedRecreate bool
+	}{
+		{
+			name:             "all changes rolled back",
+			createErrors:     map[string]error{"fail.example.com": wapiErr},
+			rolledBack:       3,
+			expectedRecreate: true,
+		},
+		{
+			name:           "rollback continues after a failed undo",
+			createErrors:   map[string]error{"fail.example.com": wapiErr, "old.example.com": wapiErr},
+			rolledBack:     2,
+			rollbackErrors: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockIBConnector{
+				mockInfobloxZones: &[]ibclient.ZoneAuth{
+					createMockInfobloxZone("example.com"),
+				},
+				mockInfobloxObjects: &[]ibclient.IBObject{
+					withTTL(createMockInfobloxObjectWithZone("ttl.example.com", endpoint.RecordTypeA, "2.2.2.2", "example.com"), 300),
+					withTTL(createMockInfobloxObjectWithZone("old.example.com", endpoint.RecordTypeA, "1.1.1.1", "example.com"), 300),
+				},
+				createErrors: tt.createErrors,
+			}
+			providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "default", false, false, client)
+			providerCfg.config.Transactional = true
+			providerCfg.config.ExtAttrsJSON = `{"owner": "team-b"}`
+
+			err := providerCfg.submitChanges(context.Background(), newChanges())
+
+			var txErr *TransactionError
+			require.True(t, errors.As(err, &txErr))
+			assert.Equal(t, 3, txErr.Applied)
+			assert.Equal(t, tt.rolledBack, txErr.RolledBack)
+			assert.Len(t, txErr.RollbackErrors, tt.rollbackErrors)
+			var changeErr *ChangeError
+			require.True(t, errors.As(err, &changeErr))
+			assert.Equal(t, "fail.example.com", changeErr.Endpoint.DNSName)
+			assert.Contains(t, err.Error(), "applied changes")
+
+			// the report of the rollback holds the failed change and the failed undos
+			out, err := json.Marshal(txErr)
+			require.NoError(t, err)
+			var report struct {
+				Failed         changeErrorReport   `json:"failed"`
+				Applied        int                 `json:"applied"`
+				RolledBack     int                 `json:"rolledBack"`
+				RollbackFailed []changeErrorReport `json:"rollbackFailed"`
+			}
+			require.NoError(t, json.Unmarshal(out, &report))
+			assert.Equal(t, "fail.example.com", report.Failed.DNSName)
+			assert.Equal(t, 3, report.Applied)
+			assert.Equal(t, tt.rolledBack, report.RolledBack)
+			assert.Len(t, report.RollbackFailed, tt.rollbackErrors)
+
+			// the created record is deleted again
+			require.Len(t, client.deletedRefs, 2)
+			assert.Contains(t, client.deletedRefs[1], "added.example.com")
+
+			// the updated record gets its prior TTL and extensible attributes back
+			require.Len(t, client.updatedObjects, 2)
+			restored := client.updatedObjects[1].(*ibclient.RecordA)
+			assert.Equal(t, "ttl.example.com", AsString(restored.Name))
+			assert.Equal(t, int64(300), AsInt64(restored.Ttl))
+			assert.Equal(t, ibclient.EA{"owner": "team-a"}, restored.Ea)
+			assert.Empty(t, restored.View)
+
+			// the deleted record is created again in the view
+			if tt.expectedRecreate {
+				recreated := client.createdObjects[len(client.createdObjects)-1].(*ibclient.RecordA)
+				assert.Equal(t, "old.example.com", AsString(recreated.Name))
+				assert.Equal(t, "1.1.1.1", AsString(recreated.Ipv4Addr))
+				assert.Equal(t, int64(300), AsInt64(recreated.Ttl))
+				assert.Equal(t, ibclient.EA{"owner": "team-a"}, recreated.Ea)
+				assert.Equal(t, "default", recreated.View)
+			}
+		})
+	}
+}
+
+func TestInfobloxSubmitChangesWithoutTransaction(t *testing.T) {
+	client := &mockIBConnector{
+		mockInfobloxZones: &[]ibclient.ZoneAuth{
+			createMockInfobloxZone("example.com"),
+		},
+		mockInfobloxObjects: &[]ibclient.IBObject{},
+		createErrors:        map[string]error{"fail.example.com": errors.New("WAPI request error: 400 Bad Request")},
+	}
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, client)
+
+	err := providerCfg.submitChanges(context.Background(), []*infobloxChange{
+		{Action: infobloxCreate, Endpoint: endpoint.NewEndpoint("added.example.com", endpoint.RecordTypeA, "1.2.3.4")},
+		{Action: infobloxCreate, Endpoint: endpoint.NewEndpoint("fail.example.com", endpoint.RecordTypeA, "9.9.9.9")},
+	})
+
+	var txErr *TransactionError
+	assert.False(t, errors.As(err, &txErr))
+	var changeErr *ChangeError
+	require.True(t, errors.As(err, &changeErr))
+	assert.Empty(t, client.deletedRefs)
+}
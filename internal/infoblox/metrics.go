@@ -0,0 +1,137 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"errors"
+	"strings"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+const metricsNamespace = "infoblox"
+
+var (
+	recordsCacheHits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "records_cache",
+		Name:      "hits_total",
+		Help:      "Number of zones whose records were served from the records cache.",
+	}, []string{"view"})
+
+	recordsCacheMisses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "records_cache",
+		Name:      "misses_total",
+		Help:      "Number of zones whose records were fetched from Infoblox as they were not cached or stale.",
+	}, []string{"view"})
+
+	circuitBreakerOpen = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "circuit_breaker",
+		Name:      "open",
+		Help:      "Whether the circuit breaker of the WAPI requests is open (1) or closed (0).",
+	})
+
+	rateLimiterWait = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "rate_limiter",
+		Name:      "wait_seconds",
+		Help:      "Time WAPI requests waited for a token of the rate limit budget.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 8),
+	}, []string{"budget"})
+
+	wapiRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "wapi",
+		Name:      "requests_total",
+		Help:      "Number of WAPI requests by object type, method and result.",
+	}, []string{"object_type", "method", "result"})
+
+	wapiRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "wapi",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the WAPI requests by object type and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"object_type", "method"})
+
+	recordsGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "records",
+		Help:      "Number of records returned by the last successful records request, by zone and record type.",
+	}, []string{"zone", "type"})
+
+	changesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "changes_total",
+		Help:      "Number of changes requested by external-dns, by action.",
+	}, []string{"action"})
+
+	changeFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "change_failures_total",
+		Help:      "Number of changes which failed, by action.",
+	}, []string{"action"})
+
+	applyChangesFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "apply_changes_failures_total",
+		Help:      "Number of requests to apply changes which failed.",
+	})
+)
+
+// observeRecords sets the number of records per zone and record type, the records are in the order of the zones
+func observeRecords(zones []ibclient.ZoneAuth, zoneRecords [][]*endpoint.Endpoint) {
+	recordsGauge.Reset()
+	for i, zone := range zones {
+		counts := map[string]int{}
+		for _, ep := range zoneRecords[i] {
+			counts[ep.RecordType]++
+		}
+		for recordType, count := range counts {
+			recordsGauge.WithLabelValues(zone.Fqdn, recordType).Set(float64(count))
+		}
+	}
+}
+
+// observeChanges counts the changes by action and, if applying them failed, the failed changes
+func observeChanges(submitted []*infobloxChange, err error) {
+	for _, change := range submitted {
+		changesTotal.WithLabelValues(strings.ToLower(change.Action)).Inc()
+	}
+	if err == nil {
+		return
+	}
+	applyChangesFailures.Inc()
+	var changesErr *ChangesError
+	var changeErr *ChangeError
+	switch {
+	case errors.As(err, &changesErr):
+		for _, failed := range changesErr.Errors {
+			changeFailures.WithLabelValues(strings.ToLower(failed.Action)).Inc()
+		}
+	case errors.As(err, &changeErr):
+		changeFailures.WithLabelValues(strings.ToLower(changeErr.Action)).Inc()
+	}
+}
@@ -0,0 +1,101 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+)
+
+func TestInstrumentedConnector(t *testing.T) {
+	client := newInstrumentedConnector(&scriptedConnector{script: []error{nil, ibclient.NewNotFoundError("not found"), errServiceUnavailable}})
+	gets := testutil.ToFloat64(wapiRequests.WithLabelValues("record:a", "get", "success"))
+	notFound := testutil.ToFloat64(wapiRequests.WithLabelValues("record:cname", "get", "not_found"))
+	failedDeletes := testutil.ToFloat64(wapiRequests.WithLabelValues("record:txt", "delete", "error"))
+	latencies := histogramSampleCount(t, wapiRequestDuration.WithLabelValues("record:txt", "delete"))
+
+	require.NoError(t, client.GetObject(ibclient.NewEmptyRecordA(), "", nil, nil))
+	assert.True(t, isNotFoundError(client.GetObject(ibclient.NewEmptyRecordCNAME(), "", nil, nil)))
+	_, err := client.DeleteObject("record:txt/ZG5z:txt.example.com/default")
+	assert.Equal(t, errServiceUnavailable, err)
+
+	assert.Equal(t, gets+1, testutil.ToFloat64(wapiRequests.WithLabelValues("record:a", "get", "success")))
+	assert.Equal(t, notFound+1, testutil.ToFloat64(wapiRequests.WithLabelValues("record:cname", "get", "not_found")))
+	assert.Equal(t, failedDeletes+1, testutil.ToFloat64(wapiRequests.WithLabelValues("record:txt", "delete", "error")))
+	assert.Equal(t, latencies+1, histogramSampleCount(t, wapiRequestDuration.WithLabelValues("record:txt", "delete")))
+
+	_, err = client.SendMultiRequest(ibclient.NewMultiRequest(nil))
+	assert.ErrorIs(t, err, errMultiRequestUnsupported)
+}
+
+func TestInfobloxRecordsMetrics(t *testing.T) {
+	providerCfg, client := newCacheTestProvider()
+	*client.mockInfobloxObjects = append(*client.mockInfobloxObjects,
+		createMockInfobloxObjectWithZone("b.example.com", endpoint.RecordTypeA, "1.1.1.2", "example.com"),
+		createMockInfobloxObjectWithZone("www.example.com", endpoint.RecordTypeCNAME, "a.example.com", "example.com"))
+
+	_, err := providerCfg.Records(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, testutil.ToFloat64(recordsGauge.WithLabelValues("example.com", endpoint.RecordTypeA)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(recordsGauge.WithLabelValues("example.com", endpoint.RecordTypeCNAME)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(recordsGauge.WithLabelValues("other.com", endpoint.RecordTypeA)))
+	assert.Equal(t, 3, testutil.CollectAndCount(recordsGauge))
+}
+
+func TestInfobloxApplyChangesMetrics(t *testing.T) {
+	client := &mockIBConnector{
+		mockInfobloxZones: &[]ibclient.ZoneAuth{
+			createMockInfobloxZone("example.com"),
+		},
+		mockInfobloxObjects: &[]ibclient.IBObject{},
+		createErrors:        map[string]error{"fail.example.com": errors.New("WAPI request error: 400('400 Bad Request')\nContents:\n")},
+	}
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, client)
+	providerCfg.config.ContinueOnError = true
+	creates := testutil.ToFloat64(changesTotal.WithLabelValues("create"))
+	failedCreates := testutil.ToFloat64(changeFailures.WithLabelValues("create"))
+	failures := testutil.ToFloat64(applyChangesFailures)
+
+	require.NoError(t, providerCfg.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{endpoint.NewEndpoint("new.example.com", endpoint.RecordTypeA, "1.2.3.4")},
+	}))
+	assert.Equal(t, creates+1, testutil.ToFloat64(changesTotal.WithLabelValues("create")))
+	assert.Equal(t, failures, testutil.ToFloat64(applyChangesFailures))
+
+	err := providerCfg.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("fail.example.com", endpoint.RecordTypeA, "9.9.9.9"),
+			endpoint.NewEndpoint("other.example.com", endpoint.RecordTypeA, "1.2.3.5"),
+		},
+	})
+	require.Error(t, err)
+	assert.Equal(t, creates+3, testutil.ToFloat64(changesTotal.WithLabelValues("create")))
+	assert.Equal(t, failedCreates+1, testutil.ToFloat64(changeFailures.WithLabelValues("create")))
+	assert.Equal(t, failures+1, testutil.ToFloat64(applyChangesFailures))
+}
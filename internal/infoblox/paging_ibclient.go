@@ -19,23 +19,69 @@ Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
 */
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"reflect"
+	"strconv"
+	"time"
 
 	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// defaultPageSize is the page size of WAPI when _max_results is not given
+const defaultPageSize = 1000
+
+// PagingConfig configures the paging of PagingGetObject. The zero value fetches pages of the
+// default size, without a page limit or retries.
+type PagingConfig struct {
+	// PageSize is the number of objects per page
+	PageSize int
+	// MaxPages guards against a WAPI which never stops returning a next page, 0 disables the guard
+	MaxPages int
+	// Retries is the number of times a failed page fetch is retried
+	Retries int
+	// RetryDelay is the delay before the first retry, it grows linearly with every retry
+	RetryDelay time.Duration
+}
+
+// PagingGetObject fetches all pages of the objects into res, see PagingGetObjectFunc
 func PagingGetObject[T any](
+	ctx context.Context,
 	c ibclient.IBConnector,
+	cfg PagingConfig,
 	obj ibclient.IBObject,
 	ref string,
 	queryParams map[string]string,
 	res *[]T,
-) (err error) {
+) error {
+	return PagingGetObjectFunc(ctx, c, cfg, obj, ref, queryParams, func(page []T) error {
+		*res = append(*res, page...)
+		return nil
+	})
+}
 
-	pagingResponse := pagingResponseStruct[T]{
-		NextPageId: "",
-		Result:     make([]T, 0),
+// PagingGetObjectFunc fetches the objects page by page and passes each page to fn, so the
+// objects need not be kept in memory all at once. An error returned by fn stops the paging.
+// An object fetched by its reference is passed on as a page of its own. The requests are bound
+// to the context, which is checked before each page.
+func PagingGetObjectFunc[T any](
+	ctx context.Context,
+	c ibclient.IBConnector,
+	cfg PagingConfig,
+	obj ibclient.IBObject,
+	ref string,
+	queryParams map[string]string,
+	fn func(page []T) error,
+) error {
+	c = connectorWithContext(ctx, c)
+	if ref != "" {
+		var result T
+		if err := getPage(ctx, c, cfg, obj, ref, ibclient.NewQueryParams(false, queryParams), &result); err != nil {
+			return fmt.Errorf("could not fetch object: %w", err)
+		}
+		return fn([]T{result})
 	}
 
 	//copy query params and update them
@@ -43,33 +89,62 @@ func PagingGetObject[T any](
 	for k, v := range queryParams {
 		queryParamsCopy[k] = v
 	}
-
 	queryParamsCopy["_return_as_object"] = "1"
 	queryParamsCopy["_paging"] = "1"
-	queryParamsCopy["_max_results"] = "1000"
+	queryParamsCopy["_max_results"] = strconv.Itoa(cfg.pageSize())
 
-	err = c.GetObject(obj, "", ibclient.NewQueryParams(false, queryParamsCopy), &pagingResponse)
-	if err != nil {
-		return fmt.Errorf("could not fetch object: %s", err)
-	} else {
-		*res = append(*res, pagingResponse.Result...)
-	}
-
-	for {
+	for page := 1; ; page++ {
+		if cfg.MaxPages > 0 && page > cfg.MaxPages {
+			return fmt.Errorf("could not fetch object: %s exceeds the maximum of %d pages", obj.ObjectType(), cfg.MaxPages)
+		}
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("could not fetch object: %w", err)
+		}
+		pagingResponse := pagingResponseStruct[T]{
+			Result: make([]T, 0),
+		}
+		_, span := startSpan(ctx, "infoblox.page",
+			attribute.String("infoblox.object_type", obj.ObjectType()), attribute.Int("infoblox.page", page))
+		err := getPage(ctx, c, cfg, obj, "", ibclient.NewQueryParams(false, queryParamsCopy), &pagingResponse)
+		span.SetAttributes(attribute.Int("infoblox.objects", len(pagingResponse.Result)))
+		endSpan(span, err)
+		if err != nil {
+			return fmt.Errorf("could not fetch object: %w", err)
+		}
+		log.Debugf("fetched page %d of %s with %d objects", page, obj.ObjectType(), len(pagingResponse.Result))
+		if err = fn(pagingResponse.Result); err != nil {
+			return err
+		}
 		if pagingResponse.NextPageId == "" {
-			return
+			return nil
 		}
 		queryParamsCopy["_page_id"] = pagingResponse.NextPageId
-		pagingResponse.NextPageId = ""
-		pagingResponse.Result = make([]T, 0)
-		err = c.GetObject(obj, "", ibclient.NewQueryParams(false, queryParamsCopy), &pagingResponse)
-		if err != nil {
-			return fmt.Errorf("could not fetch object: %s", err)
+	}
+}
+
+// getPage fetches a page, retrying failed fetches. Objects which are not found, an open circuit
+// breaker and the cancellation of the context are not retried.
+func getPage(ctx context.Context, c ibclient.IBConnector, cfg PagingConfig, obj ibclient.IBObject, ref string, queryParams *ibclient.QueryParams, res interface{}) error {
+	for attempt := 1; ; attempt++ {
+		err := c.GetObject(obj, ref, queryParams, res)
+		if err == nil || isNotFoundError(err) || errors.Is(err, errCircuitOpen) || attempt > cfg.Retries || ctx.Err() != nil {
+			return err
 		}
+		delay := time.Duration(attempt) * cfg.RetryDelay
+		log.Debugf("could not fetch page of %s, retrying in %s: %s", obj.ObjectType(), delay, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
 
-		*res = append(*res, pagingResponse.Result...)
-		fmt.Print(fmt.Sprintln("Paging to retrieve", reflect.TypeOf(obj), len(*res)))
+func (cfg PagingConfig) pageSize() int {
+	if cfg.PageSize > 0 {
+		return cfg.PageSize
 	}
+	return defaultPageSize
 }
 
 type pagingResponseStruct[T any] struct {
@@ -0,0 +1,218 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"testing"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagingTestConnector serves A records in pages, the page ID is the index of the next page.
+// The fetches of a page listed in failures fail that many times.
+type pagingTestConnector struct {
+	ibclient.IBConnector
+	pages    [][]string
+	endless  bool
+	failures map[string]int
+	requests []url.Values
+	refs     []string
+}
+
+func (c *pagingTestConnector) GetObject(obj ibclient.IBObject, ref string, queryParams *ibclient.QueryParams, res interface{}) error {
+	req, err := (&ExtendedRequestBuilder{}).BuildRequest(ibclient.GET, obj, ref, queryParams)
+	if err != nil {
+		return err
+	}
+	query := req.URL.Query()
+	c.requests = append(c.requests, query)
+	c.refs = append(c.refs, ref)
+	if ref != "" {
+		*res.(*ibclient.RecordA) = ibclient.RecordA{Ref: ref}
+		return nil
+	}
+
+	pageID := query.Get("_page_id")
+	if c.failures[pageID] > 0 {
+		c.failures[pageID]--
+		return errors.New("WAPI request error: 503 Service Unavailable")
+	}
+	page, _ := strconv.Atoi(pageID)
+	response := res.(*pagingResponseStruct[ibclient.RecordA])
+	for _, name := range c.pages[page] {
+		response.Result = append(response.Result, ibclient.RecordA{Name: &name})
+	}
+	if c.endless || page+1 < len(c.pages) {
+		response.NextPageId = strconv.Itoa(page + 1)
+		if c.endless {
+			response.NextPageId = "0"
+		}
+	}
+	return nil
+}
+
+func newPagingTestConnector() *pagingTestConnector {
+	return &pagingTestConnector{
+		pages: [][]string{
+			{"a.example.com", "b.example.com"},
+			{"c.example.com", "d.example.com"},
+			{"e.example.com"},
+		},
+		failures: map[string]int{},
+	}
+}
+
+func recordNames(records []ibclient.RecordA) []string {
+	names := make([]string, 0, len(records))
+	for _, record := range records {
+		names = append(names, AsString(record.Name))
+	}
+	return names
+}
+
+func TestPagingGetObject(t *testing.T) {
+	client := newPagingTestConnector()
+
+	var res []ibclient.RecordA
+	err := PagingGetObject(context.Background(), client, PagingConfig{PageSize: 2}, ibclient.NewEmptyRecordA(), "", map[string]string{"zone": "example.com"}, &res)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.example.com", "b.example.com", "c.example.com", "d.example.com", "e.example.com"}, recordNames(res))
+
+	require.Len(t, client.requests, 3)
+	for i, query := range client.requests {
+		assert.Equal(t, "2", query.Get("_max_results"))
+		assert.Equal(t, "1", query.Get("_paging"))
+		assert.Equal(t, "example.com", query.Get("zone"))
+		if i > 0 {
+			assert.Equal(t, strconv.Itoa(i), query.Get("_page_id"))
+		}
+	}
+}
+
+func TestPagingGetObjectDefaultPageSize(t *testing.T) {
+	client := newPagingTestConnector()
+
+	var res []ibclient.RecordA
+	require.NoError(t, PagingGetObject(context.Background(), client, PagingConfig{}, ibclient.NewEmptyRecordA(), "", nil, &res))
+	assert.Equal(t, strconv.Itoa(defaultPageSize), client.requests[0].Get("_max_results"))
+}
+
+func TestPagingGetObjectRef(t *testing.T) {
+	client := newPagingTestConnector()
+
+	var res []ibclient.RecordA
+	ref := "record:a/ZG5z:a.example.com/default"
+	require.NoError(t, PagingGetObject(context.Background(), client, PagingConfig{}, ibclient.NewEmptyRecordA(), ref, nil, &res))
+	assert.Equal(t, []string{ref}, client.refs)
+	assert.Equal(t, []ibclient.RecordA{{Ref: ref}}, res)
+	// an object fetched by its reference is not paged
+	assert.Empty(t, client.requests[0].Get("_paging"))
+}
+
+func TestPagingGetObjectMaxPages(t *testing.T) {
+	client := newPagingTestConnector()
+	client.endless = true
+
+	var res []ibclient.RecordA
+	err := PagingGetObject(context.Background(), client, PagingConfig{MaxPages: 5}, ibclient.NewEmptyRecordA(), "", nil, &res)
+	assert.EqualError(t, err, "could not fetch object: record:a exceeds the maximum of 5 pages")
+	assert.Len(t, client.requests, 5)
+}
+
+func TestPagingGetObjectRetries(t *testing.T) {
+	t.Run("a failed page is fetched again", func(t *testing.T) {
+		client := newPagingTestConnector()
+		client.failures["1"] = 2
+
+		var res []ibclient.RecordA
+		require.NoError(t, PagingGetObject(context.Background(), client, PagingConfig{Retries: 2}, ibclient.NewEmptyRecordA(), "", nil, &res))
+		assert.Len(t, res, 5)
+		// the first page is not fetched again
+		assert.Len(t, client.requests, 5)
+	})
+
+	t.Run("the retries are limited", func(t *testing.T) {
+		client := newPagingTestConnector()
+		client.failures["1"] = 2
+
+		var res []ibclient.RecordA
+		err := PagingGetObject(context.Background(), client, PagingConfig{Retries: 1}, ibclient.NewEmptyRecordA(), "", nil, &res)
+		assert.EqualError(t, err, "could not fetch object: WAPI request error: 503 Service Unavailable")
+		assert.Len(t, client.requests, 3)
+	})
+
+	t.Run("objects which are not found are not retried", func(t *testing.T) {
+		client := &pagingNotFoundConnector{}
+
+		var res []ibclient.RecordA
+		err := PagingGetObject(context.Background(), client, PagingConfig{Retries: 3}, ibclient.NewEmptyRecordA(), "", nil, &res)
+		assert.True(t, isNotFoundError(err))
+		assert.Equal(t, 1, client.requests)
+	})
+}
+
+// pagingNotFoundConnector finds no object
+type pagingNotFoundConnector struct {
+	ibclient.IBConnector
+	requests int
+}
+
+func (c *pagingNotFoundConnector) GetObject(ibclient.IBObject, string, *ibclient.QueryParams, interface{}) error {
+	c.requests++
+	return ibclient.NewNotFoundError("not found")
+}
+
+func TestPagingGetObjectFunc(t *testing.T) {
+	client := newPagingTestConnector()
+
+	var pages [][]string
+	err := PagingGetObjectFunc(context.Background(), client, PagingConfig{}, ibclient.NewEmptyRecordA(), "", nil, func(page []ibclient.RecordA) error {
+		pages = append(pages, recordNames(page))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"a.example.com", "b.example.com"}, {"c.example.com", "d.example.com"}, {"e.example.com"}}, pages)
+
+	// an error of the callback stops the paging
+	client.requests = nil
+	stop := errors.New("stop")
+	err = PagingGetObjectFunc(context.Background(), client, PagingConfig{}, ibclient.NewEmptyRecordA(), "", nil, func([]ibclient.RecordA) error {
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Len(t, client.requests, 1)
+}
+
+func TestPagingGetObjectCancelled(t *testing.T) {
+	client := newPagingTestConnector()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := PagingGetObjectFunc(ctx, client, PagingConfig{}, ibclient.NewEmptyRecordA(), "", nil, func([]ibclient.RecordA) error {
+		cancel()
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, client.requests, 1)
+}
@@ -0,0 +1,173 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+var errMultiRequestUnsupported = errors.New("the connector does not support multi-requests")
+
+// rateLimiterSlowWait is the wait for a token from which the limiter is logged as the bottleneck
+const rateLimiterSlowWait = time.Second
+
+// RateLimitConfig configures the token buckets which limit the WAPI requests. A rate of 0
+// disables the bucket.
+type RateLimitConfig struct {
+	// RequestRate and RequestBurst limit all requests
+	RequestRate  float64
+	RequestBurst int
+	// ReadRate and ReadBurst limit the reads, in addition to the limit of all requests
+	ReadRate  float64
+	ReadBurst int
+	// WriteRate and WriteBurst limit the creates, updates, deletes and multi-requests, in
+	// addition to the limit of all requests. A multi-request takes a token per operation.
+	WriteRate  float64
+	WriteBurst int
+}
+
+func (cfg RateLimitConfig) enabled() bool {
+	return cfg.RequestRate > 0 || cfg.ReadRate > 0 || cfg.WriteRate > 0
+}
+
+// rateBudget is a token bucket of requests per second. A nil budget does not limit the requests.
+type rateBudget struct {
+	name    string
+	limiter *rate.Limiter
+	// sometimes throttles the logging of the budget as the bottleneck
+	sometimes *rate.Sometimes
+}
+
+func newRateBudget(name string, requestsPerSecond float64, burst int) *rateBudget {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	return &rateBudget{
+		name:      name,
+		limiter:   rate.NewLimiter(rate.Limit(requestsPerSecond), max(burst, 1)),
+		sometimes: &rate.Sometimes{Interval: time.Minute},
+	}
+}
+
+// wait takes n tokens of the budget. The limiter never grants more than its burst at once, so
+// the tokens are taken in chunks of the burst.
+func (b *rateBudget) wait(ctx context.Context, n int) error {
+	if b == nil {
+		return nil
+	}
+	start := time.Now()
+	for remaining := max(n, 1); remaining > 0; remaining -= b.limiter.Burst() {
+		// the limiter only fails for a cancelled context, or one whose deadline passes before
+		// the tokens are available
+		if err := b.limiter.WaitN(ctx, min(remaining, b.limiter.Burst())); err != nil {
+			return err
+		}
+	}
+	waited := time.Since(start)
+	rateLimiterWait.WithLabelValues(b.name).Observe(waited.Seconds())
+	if waited >= rateLimiterSlowWait {
+		b.sometimes.Do(func() {
+			log.Infof("WAPI requests are throttled by the %s rate limit of %g per second with a burst of %d, waited %s for a token",
+				b.name, float64(b.limiter.Limit()), b.limiter.Burst(), waited)
+		})
+	}
+	return nil
+}
+
+// rateLimitedConnector limits the rate of the WAPI requests sent through the wrapped connector.
+// All requests of the provider share the budgets, whichever goroutine sends them. Reads and
+// writes take a token of their own budget first, then one of the budget of all requests. A
+// multi-request takes a token per operation it carries, in chunks of the bursts of the budgets. A
+// request bound to a context stops waiting for the limiter when the context is cancelled.
+type rateLimitedConnector struct {
+	ibclient.IBConnector
+	requests *rateBudget
+	reads    *rateBudget
+	writes   *rateBudget
+	ctx      context.Context
+}
+
+func newRateLimitedConnector(connector ibclient.IBConnector, cfg RateLimitConfig) *rateLimitedConnector {
+	return &rateLimitedConnector{
+		IBConnector: connector,
+		requests:    newRateBudget("request", cfg.RequestRate, cfg.RequestBurst),
+		reads:       newRateBudget("read", cfg.ReadRate, cfg.ReadBurst),
+		writes:      newRateBudget("write", cfg.WriteRate, cfg.WriteBurst),
+		ctx:         context.Background(),
+	}
+}
+
+func (c *rateLimitedConnector) WithContext(ctx context.Context) ibclient.IBConnector {
+	bound := *c
+	bound.IBConnector = connectorWithContext(ctx, c.IBConnector)
+	bound.ctx = ctx
+	return &bound
+}
+
+func (c *rateLimitedConnector) wait(budget *rateBudget, n int) error {
+	if err := budget.wait(c.ctx, n); err != nil {
+		return err
+	}
+	return c.requests.wait(c.ctx, n)
+}
+
+func (c *rateLimitedConnector) CreateObject(obj ibclient.IBObject) (string, error) {
+	if err := c.wait(c.writes, 1); err != nil {
+		return "", err
+	}
+	return c.IBConnector.CreateObject(obj)
+}
+
+func (c *rateLimitedConnector) GetObject(obj ibclient.IBObject, ref string, queryParams *ibclient.QueryParams, res interface{}) error {
+	if err := c.wait(c.reads, 1); err != nil {
+		return err
+	}
+	return c.IBConnector.GetObject(obj, ref, queryParams, res)
+}
+
+func (c *rateLimitedConnector) DeleteObject(ref string) (string, error) {
+	if err := c.wait(c.writes, 1); err != nil {
+		return "", err
+	}
+	return c.IBConnector.DeleteObject(ref)
+}
+
+func (c *rateLimitedConnector) UpdateObject(obj ibclient.IBObject, ref string) (string, error) {
+	if err := c.wait(c.writes, 1); err != nil {
+		return "", err
+	}
+	return c.IBConnector.UpdateObject(obj, ref)
+}
+
+func (c *rateLimitedConnector) SendMultiRequest(req *ibclient.MultiRequest) ([]map[string]interface{}, error) {
+	multi, ok := c.IBConnector.(multiRequestConnector)
+	if !ok {
+		return nil, errMultiRequestUnsupported
+	}
+	if err := c.wait(c.writes, len(req.Body)); err != nil {
+		return nil, err
+	}
+	return multi.SendMultiRequest(req)
+}
@@ -0,0 +1,145 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitedConnector(t *testing.T) {
+	client := &mockIBConnector{
+		mockInfobloxObjects: &[]ibclient.IBObject{},
+	}
+	connector := newRateLimitedConnector(client, RateLimitConfig{RequestRate: 50})
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		var res []ibclient.RecordA
+		_ = connector.GetObject(ibclient.NewEmptyRecordA(), "", ibclient.NewQueryParams(false, map[string]string{"name": "a.example.com"}), &res)
+	}
+	// the first request is sent at once, the other five wait 20ms each
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	assert.Len(t, client.getObjectRequests, 6)
+
+	_, err := connector.SendMultiRequest(ibclient.NewMultiRequest(nil))
+	assert.NoError(t, err)
+	assert.Len(t, client.multiRequests, 1)
+
+	_, err = newRateLimitedConnector(&rateLimitTestConnector{}, RateLimitConfig{RequestRate: 50}).SendMultiRequest(ibclient.NewMultiRequest(nil))
+	assert.ErrorIs(t, err, errMultiRequestUnsupported)
+}
+
+func TestRateLimitedConnectorBudgets(t *testing.T) {
+	client := &scriptedConnector{}
+	// the read takes the only token of its budget, the writes have a budget of their own
+	connector := newRateLimitedConnector(client, RateLimitConfig{ReadRate: 0.001, ReadBurst: 1, WriteRate: 100, WriteBurst: 3})
+	require.NoError(t, connector.GetObject(ibclient.NewEmptyRecordA(), "", nil, &[]ibclient.RecordA{}))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := connector.CreateObject(ibclient.NewEmptyRecordA())
+		require.NoError(t, err)
+	}
+	// the burst of the writes is sent at once
+	assert.Less(t, time.Since(start), 20*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := connector.WithContext(ctx).GetObject(ibclient.NewEmptyRecordA(), "", nil, &[]ibclient.RecordA{})
+	assert.Error(t, err)
+	assert.Equal(t, 4, client.calls)
+}
+
+func TestRateLimitedConnectorSharedBudget(t *testing.T) {
+	client := &scriptedConnector{}
+	// the write waits for the budget of all requests, which the read has taken
+	connector := newRateLimitedConnector(client, RateLimitConfig{RequestRate: 0.001, RequestBurst: 1, WriteRate: 100, WriteBurst: 1})
+	require.NoError(t, connector.GetObject(ibclient.NewEmptyRecordA(), "", nil, &[]ibclient.RecordA{}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := connector.WithContext(ctx).CreateObject(ibclient.NewEmptyRecordA())
+	assert.Error(t, err)
+	assert.Equal(t, 1, client.calls)
+}
+
+func TestRateLimitedConnectorMultiRequestTokens(t *testing.T) {
+	client := &mockIBConnector{
+		mockInfobloxObjects: &[]ibclient.IBObject{},
+	}
+	connector := newRateLimitedConnector(client, RateLimitConfig{WriteRate: 0.001, WriteBurst: 5})
+	deletes := func(n int) *ibclient.MultiRequest {
+		body := make([]*ibclient.RequestBody, n)
+		for i := range body {
+			body[i] = &ibclient.RequestBody{Method: "DELETE", Object: "record:a/ZG5z:a.example.com/default"}
+		}
+		return ibclient.NewMultiRequest(body)
+	}
+
+	// a multi-request takes a token per operation, the delete takes the last one
+	_, err := connector.SendMultiRequest(deletes(4))
+	require.NoError(t, err)
+	_, err = connector.DeleteObject("record:a/ZG5z:a.example.com/default")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = connector.WithContext(ctx).(multiRequestConnector).SendMultiRequest(deletes(1))
+	assert.Error(t, err)
+	assert.Len(t, client.multiRequests, 1)
+
+	// a multi-request larger than the burst waits for all its tokens, a burst at a time: the
+	// first token is taken at once, the other five take 20ms each
+	connector = newRateLimitedConnector(client, RateLimitConfig{WriteRate: 50, WriteBurst: 1})
+	start := time.Now()
+	_, err = connector.SendMultiRequest(deletes(6))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	assert.Len(t, client.multiRequests, 2)
+}
+
+func TestRateLimitedConnectorWaitMetric(t *testing.T) {
+	connector := newRateLimitedConnector(&scriptedConnector{}, RateLimitConfig{WriteRate: 50, WriteBurst: 1})
+	waits := histogramSampleCount(t, rateLimiterWait.WithLabelValues("write"))
+
+	for i := 0; i < 2; i++ {
+		_, err := connector.DeleteObject("record:a/ZG5z:a.example.com/default")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, waits+2, histogramSampleCount(t, rateLimiterWait.WithLabelValues("write")))
+}
+
+func histogramSampleCount(t *testing.T, observer prometheus.Observer) uint64 {
+	metric := &dto.Metric{}
+	require.NoError(t, observer.(prometheus.Metric).Write(metric))
+	return metric.GetHistogram().GetSampleCount()
+}
+
+// rateLimitTestConnector is a connector without multi-request support
+type rateLimitTestConnector struct {
+	ibclient.IBConnector
+}
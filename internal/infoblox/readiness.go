@@ -0,0 +1,211 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+// names of the readiness checks in the details of /readyz
+const (
+	readinessCheckWAPI           = "wapi"
+	readinessCheckZones          = "zones"
+	readinessCheckCache          = "cache"
+	readinessCheckCircuitBreaker = "circuitBreaker"
+)
+
+var errReadinessNotChecked = errors.New("readiness not checked yet")
+
+// readinessCheck is the result of a readiness check
+type readinessCheck struct {
+	Ready     bool      `json:"ready"`
+	Message   string    `json:"message,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+func newReadinessCheck(message string, err error) readinessCheck {
+	check := readinessCheck{Ready: err == nil, Message: message, CheckedAt: time.Now()}
+	if err != nil {
+		check.Error = err.Error()
+	}
+	return check
+}
+
+// readiness keeps the results of the last readiness checks and when the records were last
+// fetched. The zero value has no results, i.e. it is not ready.
+type readiness struct {
+	mu             sync.RWMutex
+	checks         map[string]readinessCheck
+	recordsFetched time.Time
+}
+
+// fetched records that Records succeeded, the cache is warm from then on
+func (r *readiness) fetched() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recordsFetched = time.Now()
+}
+
+func (r *readiness) lastFetched() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.recordsFetched
+}
+
+// update stores the results of the checks, and logs when the readiness changes
+func (r *readiness) update(checks map[string]readinessCheck) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	wasReady := r.checks != nil && readinessError(r.checks) == nil
+	r.checks = checks
+	err := readinessError(checks)
+	switch {
+	case err != nil:
+		log.WithError(err).Warn("provider is not ready")
+	case !wasReady:
+		log.Info("provider is ready")
+	}
+}
+
+// results returns a copy of the results of the last checks
+func (r *readiness) results() map[string]readinessCheck {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.checks == nil {
+		return nil
+	}
+	checks := make(map[string]readinessCheck, len(r.checks))
+	for name, check := range r.checks {
+		checks[name] = check
+	}
+	return checks
+}
+
+// readinessError returns the errors of the failed checks, in the order of their names
+func readinessError(checks map[string]readinessCheck) error {
+	var failed []string
+	for name, check := range checks {
+		if !check.Ready {
+			failed = append(failed, fmt.Sprintf("%s: %s", name, check.Error))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	sort.Strings(failed)
+	return errors.New(strings.Join(failed, "; "))
+}
+
+// RunReadinessChecks checks the readiness of the provider every readiness check interval, until
+// the context is cancelled. The first check runs right away.
+func (p *Provider) RunReadinessChecks(ctx context.Context) {
+	ticker := time.NewTicker(p.config.ReadinessCheckInterval)
+	defer ticker.Stop()
+	for {
+		p.checkReadiness(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkReadiness checks that the Grid answers with the configured credentials, that the zones
+// can be read and that the records cache is warm. A cold cache, or one whose records are older
+// than the cache TTL, is warmed by fetching the records, so the provider gets ready before
+// external-dns asks for them and is no longer ready once the records cannot be fetched.
+func (p *Provider) checkReadiness(ctx context.Context) {
+	checks := map[string]readinessCheck{}
+
+	var grids []ibclient.Grid
+	grid := ibclient.NewGrid(ibclient.Grid{})
+	grid.SetReturnFields([]string{"name"})
+	err := p.clientWithContext(ctx).GetObject(grid, "", ibclient.NewQueryParams(false, nil), &grids)
+	message := ""
+	if err == nil && len(grids) > 0 {
+		message = fmt.Sprintf("grid '%s' is reachable", AsString(grids[0].Name))
+	}
+	checks[readinessCheckWAPI] = newReadinessCheck(message, err)
+
+	if err = p.checkZones(ctx); err != nil {
+		err = fmt.Errorf("could not read zones: %w", err)
+	}
+	checks[readinessCheckZones] = newReadinessCheck(fmt.Sprintf("zones in view '%s' are readable", p.config.View), err)
+
+	lastFetched := p.readiness.lastFetched()
+	switch {
+	case p.cache == nil:
+		checks[readinessCheckCache] = newReadinessCheck("records cache is disabled", nil)
+	case !lastFetched.IsZero() && time.Since(lastFetched) < p.cache.ttl:
+		checks[readinessCheckCache] = newReadinessCheck(fmt.Sprintf("records fetched at %s", lastFetched.Format(time.RFC3339)), nil)
+	case checks[readinessCheckZones].Ready:
+		if _, err = p.Records(ctx); err != nil {
+			checks[readinessCheckCache] = newReadinessCheck("", fmt.Errorf("could not warm the records cache: %w", err))
+		} else {
+			checks[readinessCheckCache] = newReadinessCheck("records cache warmed", nil)
+		}
+	case lastFetched.IsZero():
+		checks[readinessCheckCache] = newReadinessCheck("", errors.New("records cache is cold"))
+	default:
+		checks[readinessCheckCache] = newReadinessCheck("", fmt.Errorf("records cache is stale, records fetched at %s", lastFetched.Format(time.RFC3339)))
+	}
+
+	p.readiness.update(checks)
+}
+
+// checkZones reads a single zone of the view, which shows that the zones can be read without
+// paging through all of them. A positive _max_results truncates the result instead of failing.
+func (p *Provider) checkZones(ctx context.Context) error {
+	var zones []ibclient.ZoneAuth
+	obj := ibclient.NewZoneAuth(ibclient.ZoneAuth{})
+	obj.SetReturnFields([]string{"fqdn"})
+	searchFields := map[string]string{"_max_results": "1"}
+	if p.config.View != "" {
+		searchFields["view"] = p.config.View
+	}
+	err := p.clientWithContext(ctx).GetObject(obj, "", ibclient.NewQueryParams(false, searchFields), &zones)
+	if err != nil && !isNotFoundError(err) {
+		return err
+	}
+	return nil
+}
+
+// Readiness reports the results of the last readiness checks, and an error unless they all
+// succeeded and the circuit breaker is closed
+func (p *Provider) Readiness() (interface{}, error) {
+	checks := p.readiness.results()
+	if checks == nil {
+		return nil, errReadinessNotChecked
+	}
+	if _, ok := p.client.(healthReporter); ok {
+		checks[readinessCheckCircuitBreaker] = newReadinessCheck("", p.Health())
+	}
+	return checks, readinessError(checks)
+}
@@ -0,0 +1,165 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/provider"
+)
+
+// readinessChecks returns the results of the checks reported by Readiness
+func readinessChecks(t *testing.T, p *Provider) (map[string]readinessCheck, error) {
+	details, err := p.Readiness()
+	checks, ok := details.(map[string]readinessCheck)
+	require.True(t, ok)
+	return checks, err
+}
+
+func TestInfobloxReadiness(t *testing.T) {
+	providerCfg, client := newCacheTestProvider()
+
+	_, err := providerCfg.Readiness()
+	assert.ErrorIs(t, err, errReadinessNotChecked)
+
+	providerCfg.checkReadiness(context.Background())
+	checks, err := readinessChecks(t, providerCfg)
+	require.NoError(t, err)
+	assert.Equal(t, "grid 'Infoblox' is reachable", checks[readinessCheckWAPI].Message)
+	assert.Equal(t, "zones in view '' are readable", checks[readinessCheckZones].Message)
+	assert.Equal(t, "records cache warmed", checks[readinessCheckCache].Message)
+	assert.NotContains(t, checks, readinessCheckCircuitBreaker)
+	// the cold cache is warmed
+	assert.Equal(t, map[string]bool{"example.com": true, "other.com": true}, client.fetchedZones())
+
+	// the warm cache is not fetched again
+	client.getObjectRequests = nil
+	providerCfg.checkReadiness(context.Background())
+	checks, err = readinessChecks(t, providerCfg)
+	require.NoError(t, err)
+	assert.Contains(t, checks[readinessCheckCache].Message, "records fetched at")
+	assert.Empty(t, client.fetchedZones())
+}
+
+func TestInfobloxReadinessZones(t *testing.T) {
+	providerCfg, client := newCacheTestProvider()
+	providerCfg.readiness.fetched()
+
+	// a single zone is read, not all zones page by page
+	providerCfg.checkReadiness(context.Background())
+	var zones []*getObjectRequest
+	for _, req := range client.getObjectRequests {
+		if req.obj == "zone_auth" {
+			zones = append(zones, req)
+		}
+	}
+	require.Len(t, zones, 1)
+	assert.Equal(t, "1", zones[0].url.Query().Get("_max_results"))
+	assert.False(t, zones[0].url.Query().Has("_paging"))
+}
+
+func TestInfobloxReadinessStaleCache(t *testing.T) {
+	providerCfg, client := newCacheTestProvider()
+	providerCfg.checkReadiness(context.Background())
+
+	// the records fetched longer than the cache TTL ago are fetched again, a failure makes the
+	// provider not ready
+	providerCfg.readiness.recordsFetched = time.Now().Add(-2 * time.Minute)
+	providerCfg.cache.invalidate("")
+	client.getObjectRequests = nil
+	providerCfg.checkReadiness(context.Background())
+	checks, err := readinessChecks(t, providerCfg)
+	require.NoError(t, err)
+	assert.Equal(t, "records cache warmed", checks[readinessCheckCache].Message)
+	assert.Equal(t, map[string]bool{"example.com": true, "other.com": true}, client.fetchedZones())
+
+	providerCfg.readiness.recordsFetched = time.Now().Add(-2 * time.Minute)
+	providerCfg.cache.invalidate("")
+	providerCfg.config.ExtAttrsJSON = "{"
+	providerCfg.checkReadiness(context.Background())
+	checks, err = readinessChecks(t, providerCfg)
+	require.Error(t, err)
+	assert.False(t, checks[readinessCheckCache].Ready)
+	assert.Contains(t, checks[readinessCheckCache].Error, "could not warm the records cache")
+}
+
+func TestInfobloxReadinessWAPIFailure(t *testing.T) {
+	providerCfg, client := newCacheTestProvider()
+	client.gridError = errors.New("WAPI request error: 401('401 Unauthorized')\nContents:\n")
+
+	providerCfg.checkReadiness(context.Background())
+	checks, err := readinessChecks(t, providerCfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "wapi: WAPI request error: 401")
+	assert.False(t, checks[readinessCheckWAPI].Ready)
+	assert.True(t, checks[readinessCheckZones].Ready)
+
+	client.gridError = nil
+	providerCfg.checkReadiness(context.Background())
+	_, err = readinessChecks(t, providerCfg)
+	assert.NoError(t, err)
+}
+
+func TestInfobloxReadinessCacheDisabled(t *testing.T) {
+	client := &mockIBConnector{
+		mockInfobloxZones:   &[]ibclient.ZoneAuth{createMockInfobloxZone("example.com")},
+		mockInfobloxObjects: &[]ibclient.IBObject{},
+	}
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, client)
+
+	providerCfg.checkReadiness(context.Background())
+	checks, err := readinessChecks(t, providerCfg)
+	require.NoError(t, err)
+	assert.Equal(t, "records cache is disabled", checks[readinessCheckCache].Message)
+	assert.Empty(t, client.fetchedZones())
+}
+
+func TestInfobloxReadinessCircuitBreaker(t *testing.T) {
+	client, _ := newScriptedRetryConnector(1, errServiceUnavailable, errServiceUnavailable, errServiceUnavailable, errServiceUnavailable)
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, client)
+	providerCfg.cache = newRecordsCache(time.Minute)
+
+	providerCfg.checkReadiness(context.Background())
+	checks, err := readinessChecks(t, providerCfg)
+	require.Error(t, err)
+	assert.False(t, checks[readinessCheckWAPI].Ready)
+	assert.False(t, checks[readinessCheckZones].Ready)
+	assert.Equal(t, "records cache is cold", checks[readinessCheckCache].Error)
+	assert.False(t, checks[readinessCheckCircuitBreaker].Ready)
+}
+
+func TestInfobloxRunReadinessChecks(t *testing.T) {
+	providerCfg, _ := newCacheTestProvider()
+	providerCfg.config.ReadinessCheckInterval = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// the first check runs right away, the cancelled context stops the checks
+	providerCfg.RunReadinessChecks(ctx)
+	_, err := providerCfg.Readiness()
+	assert.NotErrorIs(t, err, errReadinessNotChecked)
+}
@@ -0,0 +1,303 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+var errCircuitOpen = errors.New("the circuit breaker is open")
+
+// healthReporter is implemented by connectors which can report a degraded state
+type healthReporter interface {
+	Health() error
+}
+
+// wapiStatusCodeRegEx matches the status code of the errors of WAPI responses, see getHTTPResponseError of ibclient
+var wapiStatusCodeRegEx = regexp.MustCompile(`WAPI request error: (\d{3})`)
+
+// isTransientError reports whether the request failed because of the Grid rather than the request,
+// i.e. the Grid responded with a server error or could not be reached at all
+func isTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if match := wapiStatusCodeRegEx.FindStringSubmatch(err.Error()); match != nil {
+		code, _ := strconv.Atoi(match[1])
+		return code >= 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// RetryConfig configures the retries of failed WAPI requests and the circuit breaker
+type RetryConfig struct {
+	// MaxRetries is the number of times a failed idempotent request is retried
+	MaxRetries int
+	// BaseDelay is the delay before the first retry, doubled for every further retry
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between retries
+	MaxDelay time.Duration
+	// FailureThreshold is the number of consecutive failures which open the circuit, 0 disables the circuit breaker
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before a request is let through again
+	OpenTimeout time.Duration
+}
+
+// backoff returns the delay before the retry, exponential in the attempt with equal jitter
+func (cfg RetryConfig) backoff(attempt int) time.Duration {
+	if cfg.BaseDelay <= 0 {
+		return 0
+	}
+	delay := cfg.BaseDelay << min(attempt-1, 30)
+	if cfg.MaxDelay > 0 && delay > cfg.MaxDelay {
+		delay = cfg.MaxDelay
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker fails the requests fast while the Grid is unavailable. The circuit opens after
+// the configured number of consecutive transient failures. Once the open timeout has passed,
+// a single request is let through: its success closes the circuit, its failure opens it again.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	timeout   time.Duration
+	now       func() time.Time
+	state     circuitState
+	failures  int
+	openedAt  time.Time
+	lastErr   error
+}
+
+func newCircuitBreaker(threshold int, timeout time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		timeout:   timeout,
+		now:       time.Now,
+	}
+}
+
+// allow reports whether a request may be sent
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case circuitOpen:
+		if b.now().Sub(b.openedAt) < b.timeout {
+			return b.openError()
+		}
+		log.Infof("circuit breaker half-open, probing the Grid")
+		b.setState(circuitHalfOpen)
+		return nil
+	case circuitHalfOpen:
+		// the probe is still in flight
+		return b.openError()
+	default:
+		return nil
+	}
+}
+
+// record records the outcome of a request let through by allow
+func (b *circuitBreaker) record(err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		// the request was abandoned, it tells nothing about the Grid
+		if b.state == circuitHalfOpen {
+			b.setState(circuitOpen)
+		}
+		return
+	}
+	if !isTransientError(err) {
+		if b.state != circuitClosed {
+			log.Infof("circuit breaker closed, the Grid responds again")
+		}
+		b.failures = 0
+		b.lastErr = nil
+		b.setState(circuitClosed)
+		return
+	}
+	b.failures++
+	b.lastErr = err
+	if b.state == circuitHalfOpen || b.failures >= b.threshold {
+		if b.state != circuitOpen {
+			log.Warnf("circuit breaker open for %s after %d consecutive failures: %s", b.timeout, b.failures, err)
+		}
+		b.openedAt = b.now()
+		b.setState(circuitOpen)
+	}
+}
+
+// health returns the error which opened the circuit, nil while the circuit is closed
+func (b *circuitBreaker) health() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == circuitClosed {
+		return nil
+	}
+	return fmt.Errorf("%w after %d consecutive failed WAPI requests: %s", errCircuitOpen, b.failures, b.lastErr)
+}
+
+func (b *circuitBreaker) openError() error {
+	return fmt.Errorf("%w after %d consecutive failed WAPI requests, until %s", errCircuitOpen, b.failures, b.openedAt.Add(b.timeout).Format(time.RFC3339))
+}
+
+func (b *circuitBreaker) setState(state circuitState) {
+	b.state = state
+	if state == circuitClosed {
+		circuitBreakerOpen.Set(0)
+	} else {
+		circuitBreakerOpen.Set(1)
+	}
+}
+
+// retryConnector retries the idempotent requests sent through the wrapped connector which fail
+// with a transient error, and fails all requests fast while the circuit breaker is open. Creates
+// and multi-requests are not retried, a failed request may have been processed by the Grid.
+type retryConnector struct {
+	ibclient.IBConnector
+	cfg     RetryConfig
+	breaker *circuitBreaker
+	ctx     context.Context
+}
+
+func newRetryConnector(connector ibclient.IBConnector, cfg RetryConfig) *retryConnector {
+	c := &retryConnector{
+		IBConnector: connector,
+		cfg:         cfg,
+		ctx:         context.Background(),
+	}
+	if cfg.FailureThreshold > 0 {
+		c.breaker = newCircuitBreaker(cfg.FailureThreshold, cfg.OpenTimeout)
+	}
+	return c
+}
+
+func (c *retryConnector) WithContext(ctx context.Context) ibclient.IBConnector {
+	return &retryConnector{
+		IBConnector: connectorWithContext(ctx, c.IBConnector),
+		cfg:         c.cfg,
+		breaker:     c.breaker,
+		ctx:         ctx,
+	}
+}
+
+// Health reports a degraded state while the circuit breaker is open
+func (c *retryConnector) Health() error {
+	return c.breaker.health()
+}
+
+// do sends the request through the circuit breaker, retrying transient failures if the request is idempotent
+func (c *retryConnector) do(method string, idempotent bool, request func() error) error {
+	for attempt := 1; ; attempt++ {
+		if err := c.breaker.allow(); err != nil {
+			return err
+		}
+		err := request()
+		c.breaker.record(err)
+		if !idempotent || !isTransientError(err) || attempt > c.cfg.MaxRetries || c.ctx.Err() != nil {
+			return err
+		}
+		delay := c.cfg.backoff(attempt)
+		log.Debugf("WAPI %s request failed, retry %d of %d in %s: %s", method, attempt, c.cfg.MaxRetries, delay, err)
+		select {
+		case <-c.ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (c *retryConnector) CreateObject(obj ibclient.IBObject) (ref string, err error) {
+	err = c.do("create", false, func() error {
+		ref, err = c.IBConnector.CreateObject(obj)
+		return err
+	})
+	return ref, err
+}
+
+func (c *retryConnector) GetObject(obj ibclient.IBObject, ref string, queryParams *ibclient.QueryParams, res interface{}) error {
+	return c.do("get", true, func() error {
+		return c.IBConnector.GetObject(obj, ref, queryParams, res)
+	})
+}
+
+func (c *retryConnector) DeleteObject(ref string) (deleted string, err error) {
+	retried := false
+	err = c.do("delete", true, func() error {
+		deleted, err = c.IBConnector.DeleteObject(ref)
+		if retried && isNotFoundError(err) {
+			// the failed attempt has deleted the object after all
+			deleted, err = ref, nil
+		}
+		retried = true
+		return err
+	})
+	return deleted, err
+}
+
+func (c *retryConnector) UpdateObject(obj ibclient.IBObject, ref string) (updated string, err error) {
+	err = c.do("update", true, func() error {
+		updated, err = c.IBConnector.UpdateObject(obj, ref)
+		return err
+	})
+	return updated, err
+}
+
+func (c *retryConnector) SendMultiRequest(req *ibclient.MultiRequest) (res []map[string]interface{}, err error) {
+	multi, ok := c.IBConnector.(multiRequestConnector)
+	if !ok {
+		return nil, errMultiRequestUnsupported
+	}
+	err = c.do("multi", false, func() error {
+		res, err = multi.SendMultiRequest(req)
+		return err
+	})
+	return res, err
+}
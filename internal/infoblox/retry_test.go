@@ -0,0 +1,225 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+var (
+	errServiceUnavailable = errors.New("WAPI request error: 503('503 Service Unavailable')\nContents:\n\n")
+	errBadRequest         = errors.New("WAPI request error: 400('400 Bad Request')\nContents:\n\n")
+	errConnectionRefused  = &url.Error{Op: "Get", URL: "https://infoblox/wapi", Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}
+)
+
+// scriptedConnector fails its requests with the scripted errors, one per request, and
+// succeeds once the script is exhausted
+type scriptedConnector struct {
+	ibclient.IBConnector
+	script []error
+	calls  int
+}
+
+func (c *scriptedConnector) next() error {
+	c.calls++
+	if len(c.script) == 0 {
+		return nil
+	}
+	err := c.script[0]
+	c.script = c.script[1:]
+	return err
+}
+
+func (c *scriptedConnector) CreateObject(ibclient.IBObject) (string, error) {
+	if err := c.next(); err != nil {
+		return "", err
+	}
+	return "record:a/created", nil
+}
+
+func (c *scriptedConnector) GetObject(ibclient.IBObject, string, *ibclient.QueryParams, interface{}) error {
+	return c.next()
+}
+
+func (c *scriptedConnector) DeleteObject(ref string) (string, error) {
+	if err := c.next(); err != nil {
+		return "", err
+	}
+	return ref, nil
+}
+
+func (c *scriptedConnector) UpdateObject(_ ibclient.IBObject, ref string) (string, error) {
+	if err := c.next(); err != nil {
+		return "", err
+	}
+	return ref, nil
+}
+
+func newScriptedRetryConnector(threshold int, script ...error) (*retryConnector, *scriptedConnector) {
+	scripted := &scriptedConnector{script: script}
+	return newRetryConnector(scripted, RetryConfig{MaxRetries: 3, FailureThreshold: threshold, OpenTimeout: time.Minute}), scripted
+}
+
+func TestRetryConnectorRetries(t *testing.T) {
+	tests := []struct {
+		name          string
+		script        []error
+		expectedCalls int
+		expectedError error
+	}{
+		{name: "success", expectedCalls: 1},
+		{name: "server errors", script: []error{errServiceUnavailable, errServiceUnavailable}, expectedCalls: 3},
+		{name: "connection errors", script: []error{errConnectionRefused}, expectedCalls: 2},
+		{name: "retries exhausted", script: []error{errServiceUnavailable, errServiceUnavailable, errServiceUnavailable, errServiceUnavailable}, expectedCalls: 4, expectedError: errServiceUnavailable},
+		{name: "client errors are not retried", script: []error{errBadRequest}, expectedCalls: 1, expectedError: errBadRequest},
+		{name: "objects not found are not retried", script: []error{ibclient.NewNotFoundError("not found")}, expectedCalls: 1, expectedError: ibclient.NewNotFoundError("not found")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, scripted := newScriptedRetryConnector(0, tt.script...)
+			err := client.GetObject(ibclient.NewEmptyRecordA(), "", nil, nil)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedCalls, scripted.calls)
+		})
+	}
+}
+
+func TestRetryConnectorCreateNotRetried(t *testing.T) {
+	client, scripted := newScriptedRetryConnector(0, errServiceUnavailable)
+	_, err := client.CreateObject(ibclient.NewEmptyRecordA())
+	assert.Equal(t, errServiceUnavailable, err)
+	assert.Equal(t, 1, scripted.calls)
+}
+
+func TestRetryConnectorDeleteRetried(t *testing.T) {
+	// the failed attempt deleted the object after all
+	client, scripted := newScriptedRetryConnector(0, errServiceUnavailable, ibclient.NewNotFoundError("not found"))
+	ref, err := client.DeleteObject("record:a/deleted")
+	require.NoError(t, err)
+	assert.Equal(t, "record:a/deleted", ref)
+	assert.Equal(t, 2, scripted.calls)
+
+	// an object which never existed is not found
+	client, _ = newScriptedRetryConnector(0, ibclient.NewNotFoundError("not found"))
+	_, err = client.DeleteObject("record:a/missing")
+	assert.True(t, isNotFoundError(err))
+}
+
+func TestRetryConnectorCancelled(t *testing.T) {
+	_, scripted := newScriptedRetryConnector(0, errServiceUnavailable, errServiceUnavailable)
+	client := newRetryConnector(scripted, RetryConfig{MaxRetries: 3, BaseDelay: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	err := client.WithContext(ctx).GetObject(ibclient.NewEmptyRecordA(), "", nil, nil)
+	assert.Equal(t, errServiceUnavailable, err)
+	assert.Equal(t, 1, scripted.calls)
+}
+
+func TestRetryConnectorCircuitBreaker(t *testing.T) {
+	client, scripted := newScriptedRetryConnector(3, errServiceUnavailable, errServiceUnavailable, errServiceUnavailable, errServiceUnavailable)
+	now := time.Now()
+	client.breaker.now = func() time.Time { return now }
+	require.NoError(t, client.Health())
+
+	// the third consecutive failure opens the circuit, the requests fail fast
+	err := client.GetObject(ibclient.NewEmptyRecordA(), "", nil, nil)
+	assert.ErrorIs(t, err, errCircuitOpen)
+	assert.Equal(t, 3, scripted.calls)
+	assert.ErrorIs(t, client.Health(), errCircuitOpen)
+	_, err = client.UpdateObject(ibclient.NewEmptyRecordA(), "record:a/updated")
+	assert.ErrorIs(t, err, errCircuitOpen)
+	assert.Equal(t, 3, scripted.calls)
+
+	// after the timeout a failed probe opens the circuit again
+	now = now.Add(time.Minute)
+	_, err = client.CreateObject(ibclient.NewEmptyRecordA())
+	assert.Equal(t, errServiceUnavailable, err)
+	assert.Equal(t, 4, scripted.calls)
+	assert.ErrorIs(t, client.Health(), errCircuitOpen)
+
+	// a successful probe closes the circuit
+	now = now.Add(time.Minute)
+	_, err = client.CreateObject(ibclient.NewEmptyRecordA())
+	require.NoError(t, err)
+	require.NoError(t, client.Health())
+	require.NoError(t, client.GetObject(ibclient.NewEmptyRecordA(), "", nil, nil))
+}
+
+func TestRetryConnectorCircuitBreakerClientErrors(t *testing.T) {
+	client, _ := newScriptedRetryConnector(2, errServiceUnavailable, errBadRequest, errServiceUnavailable)
+
+	// client errors show the Grid is available, the failures are not consecutive
+	assert.Equal(t, errBadRequest, client.GetObject(ibclient.NewEmptyRecordA(), "", nil, nil))
+	require.NoError(t, client.GetObject(ibclient.NewEmptyRecordA(), "", nil, nil))
+	require.NoError(t, client.Health())
+}
+
+func TestRetryConfigBackoff(t *testing.T) {
+	cfg := RetryConfig{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt, expected := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		delay := cfg.backoff(attempt + 1)
+		assert.GreaterOrEqual(t, delay, expected/2, attempt)
+		assert.LessOrEqual(t, delay, expected, attempt)
+	}
+	assert.Zero(t, RetryConfig{}.backoff(1))
+}
+
+func TestProviderHealth(t *testing.T) {
+	client, _ := newScriptedRetryConnector(1, errServiceUnavailable)
+	providerCfg := &Provider{client: client}
+	require.NoError(t, providerCfg.Health())
+
+	_ = client.GetObject(ibclient.NewEmptyRecordA(), "", nil, nil)
+	assert.ErrorIs(t, providerCfg.Health(), errCircuitOpen)
+
+	// providers without retries are always healthy
+	assert.NoError(t, (&Provider{client: &mockIBConnector{}}).Health())
+}
+
+func TestNewInfobloxProviderPageRetries(t *testing.T) {
+	newProvider := func(maxRetries int) *Provider {
+		providerCfg, err := NewInfobloxProvider(&StartupConfig{
+			Host:                   "localhost",
+			Port:                   443,
+			Version:                "2.3.1",
+			RecordMode:             recordModeRecord,
+			PageRetries:            2,
+			MaxRetries:             maxRetries,
+			ReadinessCheckInterval: time.Minute,
+		}, endpoint.NewDomainFilter([]string{"example.com"}))
+		require.NoError(t, err)
+		return providerCfg
+	}
+
+	// the pages are retried by the retry connector only, not once more by the paging
+	assert.Equal(t, 0, newProvider(3).paging.Retries)
+	assert.Equal(t, 2, newProvider(0).paging.Retries)
+}
@@ -0,0 +1,149 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// actionOrder is the order of the actions within a zone. Deleting first frees the names of
+// replaced records, e.g. a CNAME replacing an A record can only be created once the A
+// record is gone, as a CNAME must be the only record of its name.
+var actionOrder = map[string]int{
+	infobloxDelete: 0,
+	infobloxUpdate: 1,
+	infobloxCreate: 2,
+}
+
+// zoneChanges are the changes to apply to a zone
+type zoneChanges struct {
+	zone    string
+	changes []*infobloxChange
+	// conflicts are the deletes a create depends on, see conflictingDeletes
+	conflicts map[*infobloxChange][]*infobloxChange
+	// failed are the changes which failed so far
+	failed map[*infobloxChange]bool
+}
+
+func newZoneChanges(zone string, changes []*infobloxChange) zoneChanges {
+	return zoneChanges{
+		zone:      zone,
+		changes:   sortChanges(changes),
+		conflicts: conflictingDeletes(changes),
+		failed:    map[*infobloxChange]bool{},
+	}
+}
+
+// fail records that the change failed
+func (z zoneChanges) fail(change *infobloxChange) {
+	z.failed[change] = true
+}
+
+// failedConflict returns the failed delete the change depends on, if there is one. The create
+// would fail as the record which conflicts with it is still there.
+func (z zoneChanges) failedConflict(change *infobloxChange) *infobloxChange {
+	for _, del := range z.conflicts[change] {
+		if z.failed[del] {
+			return del
+		}
+	}
+	return nil
+}
+
+// conflictingDeletes groups the changes by name and returns the deletes each create of a name
+// depends on. A CNAME must be the only record of its name, so a CNAME can only be created
+// once the other records of the name are deleted, and another record once the CNAME is.
+func conflictingDeletes(changes []*infobloxChange) map[*infobloxChange][]*infobloxChange {
+	byName := map[string][]*infobloxChange{}
+	for _, change := range changes {
+		name := strings.ToLower(change.Endpoint.DNSName)
+		byName[name] = append(byName[name], change)
+	}
+	conflicts := map[*infobloxChange][]*infobloxChange{}
+	for _, named := range byName {
+		for _, create := range named {
+			if create.Action != infobloxCreate {
+				continue
+			}
+			for _, del := range named {
+				if del.Action == infobloxDelete &&
+					(create.Endpoint.RecordType == endpoint.RecordTypeCNAME || del.Endpoint.RecordType == endpoint.RecordTypeCNAME) {
+					conflicts[create] = append(conflicts[create], del)
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+// scheduleChanges orders the changes of the zones, so they are applied in the same order
+// whatever the order of the map. Zones are processed in sorted order, the PTR changes of all
+// zones follow the changes of the forward records they point to. Within a zone, the deletes
+// precede the creates which conflict with the deleted records, and these creates are skipped
+// when such a delete fails.
+func scheduleChanges(changesByZone map[string][]*infobloxChange) []zoneChanges {
+	zones := make([]string, 0, len(changesByZone))
+	for zone := range changesByZone {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+
+	var forward, reverse []zoneChanges
+	for _, zone := range zones {
+		var forwardChanges, reverseChanges []*infobloxChange
+		for _, change := range changesByZone[zone] {
+			if change.Endpoint.RecordType == endpoint.RecordTypePTR {
+				reverseChanges = append(reverseChanges, change)
+			} else {
+				forwardChanges = append(forwardChanges, change)
+			}
+		}
+		if len(forwardChanges) > 0 {
+			forward = append(forward, newZoneChanges(zone, forwardChanges))
+		}
+		if len(reverseChanges) > 0 {
+			reverse = append(reverse, newZoneChanges(zone, reverseChanges))
+		}
+	}
+	return append(forward, reverse...)
+}
+
+// sortChanges orders the changes by action, see actionOrder, then by name, type, set identifier and target
+func sortChanges(changes []*infobloxChange) []*infobloxChange {
+	sort.SliceStable(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if actionOrder[a.Action] != actionOrder[b.Action] {
+			return actionOrder[a.Action] < actionOrder[b.Action]
+		}
+		if a.Endpoint.DNSName != b.Endpoint.DNSName {
+			return a.Endpoint.DNSName < b.Endpoint.DNSName
+		}
+		if a.Endpoint.RecordType != b.Endpoint.RecordType {
+			return a.Endpoint.RecordType < b.Endpoint.RecordType
+		}
+		if a.Endpoint.SetIdentifier != b.Endpoint.SetIdentifier {
+			return a.Endpoint.SetIdentifier < b.Endpoint.SetIdentifier
+		}
+		return a.Endpoint.Targets.String() < b.Endpoint.Targets.String()
+	})
+	return changes
+}
@@ -0,0 +1,89 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"fmt"
+	"testing"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/provider"
+)
+
+func scheduledOrder(schedule []zoneChanges) []string {
+	var order []string
+	for _, scheduled := range schedule {
+		for _, change := range scheduled.changes {
+			order = append(order, fmt.Sprintf("%s %s %s %s %s", scheduled.zone, change.Action, change.Endpoint.DNSName, change.Endpoint.RecordType, change.Endpoint.Targets))
+		}
+	}
+	return order
+}
+
+func TestScheduleChanges(t *testing.T) {
+	zones := []ibclient.ZoneAuth{
+		createMockInfobloxZone("other.com"),
+		createMockInfobloxZone("example.com"),
+		createMockInfobloxZone("1.2.3.0/24"),
+	}
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com", "other.com"}), provider.NewZoneIDFilter([]string{""}), "", false, true, &mockIBConnector{})
+
+	newChanges := func() []*infobloxChange {
+		return []*infobloxChange{
+			// www.example.com switches from an A record to a CNAME
+			{Action: infobloxCreate, Endpoint: endpoint.NewEndpoint("www.example.com", endpoint.RecordTypeCNAME, "other.com")},
+			{Action: infobloxCreate, Endpoint: endpoint.NewEndpoint("a.other.com", endpoint.RecordTypeA, "1.2.3.5")},
+			{Action: infobloxUpdate, Endpoint: endpoint.NewEndpoint("b.example.com", endpoint.RecordTypeTXT, "tag")},
+			{Action: infobloxDelete, Endpoint: endpoint.NewEndpoint("www.example.com", endpoint.RecordTypeA, "1.2.3.4")},
+			{Action: infobloxCreate, Endpoint: endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "1.2.3.6")},
+			{Action: infobloxCreate, Endpoint: endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "1.2.3.1")},
+		}
+	}
+	expected := []string{
+		"example.com DELETE www.example.com A 1.2.3.4",
+		"example.com UPDATE b.example.com TXT tag",
+		"example.com CREATE a.example.com A 1.2.3.1",
+		"example.com CREATE a.example.com A 1.2.3.6",
+		"example.com CREATE www.example.com CNAME other.com",
+		"other.com CREATE a.other.com A 1.2.3.5",
+		"1.2.3.0/24 DELETE www.example.com PTR 1.2.3.4",
+		"1.2.3.0/24 CREATE a.example.com PTR 1.2.3.1",
+		"1.2.3.0/24 CREATE a.example.com PTR 1.2.3.6",
+		"1.2.3.0/24 CREATE a.other.com PTR 1.2.3.5",
+	}
+
+	// map iteration order is random, the schedule must not be
+	for i := 0; i < 20; i++ {
+		changesByZone := providerCfg.ChangesByZone(zonePointerConverter(zones), newChanges())
+		assert.Equal(t, expected, scheduledOrder(scheduleChanges(changesByZone)))
+	}
+}
+
+func TestScheduleChangesSkipsEmptyZones(t *testing.T) {
+	schedule := scheduleChanges(map[string][]*infobloxChange{
+		"example.com": {},
+		"other.com": {
+			{Action: infobloxCreate, Endpoint: endpoint.NewEndpoint("a.other.com", endpoint.RecordTypeA, "1.2.3.5")},
+		},
+	})
+	assert.Equal(t, []string{"other.com CREATE a.other.com A 1.2.3.5"}, scheduledOrder(schedule))
+}
@@ -0,0 +1,268 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// syncObjectTypes are the object types fetched by zoneRecordFetches, whose changes are tracked
+var syncObjectTypes = []string{
+	"record:a", "record:aaaa", "record:host", "record:cname", "record:txt",
+	"record:mx", "record:srv", "record:ns", "record:caa", "record:ptr",
+}
+
+// recordsSync tracks the sequence ID of the Grid database the records cache is in sync with.
+// The cache is brought up to date with the objects changed since, once its records are older
+// than the cache TTL, and dropped as a whole every full resync interval.
+type recordsSync struct {
+	mu           sync.Mutex
+	interval     time.Duration
+	ttl          time.Duration
+	now          func() time.Time
+	sequenceID   string
+	lastSync     time.Time
+	lastFullSync time.Time
+	// records are the records of the changed objects by their unique ID. A deleted object is
+	// reported without its fields, it is attributed to the record it had in an earlier change.
+	records map[string]syncedRecord
+}
+
+// syncedRecord is the name and view of a changed record
+type syncedRecord struct {
+	name string
+	view string
+}
+
+func newRecordsSync(interval, ttl time.Duration) *recordsSync {
+	return &recordsSync{
+		interval: interval,
+		ttl:      ttl,
+		now:      time.Now,
+		records:  map[string]syncedRecord{},
+	}
+}
+
+// record returns the name and view of the changed record. The records of the objects are kept
+// until they are deleted.
+func (s *recordsSync) record(change *dbObjectChange) (name, view string, ok bool) {
+	if name, view, ok = change.record(); ok {
+		if change.UniqueID != "" {
+			s.records[change.UniqueID] = syncedRecord{name: name, view: view}
+		}
+		return name, view, true
+	}
+	record, ok := s.records[change.UniqueID]
+	if !ok {
+		return "", "", false
+	}
+	delete(s.records, change.UniqueID)
+	return record.name, record.view, true
+}
+
+// reset makes the next sync a full resync
+func (s *recordsSync) reset() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastFullSync = time.Time{}
+}
+
+// dbObjectChange is a changed object reported by db_objects. The object holds the fields of
+// the record, or "None" if WAPI cannot return the object, e.g. as it has been deleted.
+type dbObjectChange struct {
+	LastSequenceID string          `json:"last_sequence_id,omitempty"`
+	Object         json.RawMessage `json:"object,omitempty"`
+	ObjectType     string          `json:"object_type,omitempty"`
+	UniqueID       string          `json:"unique_id,omitempty"`
+}
+
+// record returns the name and view of the changed record, from its fields or its reference,
+// e.g. record:a/ZG5zLmJpbmRfYSQuX2RlZmF1bHQuY29tLmV4YW1wbGUsYSwxLjEuMS4x:a.example.com/default
+func (c *dbObjectChange) record() (name, view string, ok bool) {
+	var object struct {
+		Ref  string `json:"_ref"`
+		Name string `json:"name"`
+		View string `json:"view"`
+	}
+	if err := json.Unmarshal(c.Object, &object); err != nil {
+		return "", "", false
+	}
+	if object.Name != "" {
+		return object.Name, object.View, true
+	}
+	_, ref, found := strings.Cut(object.Ref, "/")
+	if !found {
+		return "", "", false
+	}
+	_, ref, found = strings.Cut(ref, ":")
+	if !found {
+		return "", "", false
+	}
+	name, view, _ = strings.Cut(ref, "/")
+	return name, view, name != ""
+}
+
+// dbObjectChanges returns the objects changed since the sequence ID and the sequence ID of the
+// last change
+func (p *Provider) dbObjectChanges(ctx context.Context, sequenceID string) ([]dbObjectChange, string, error) {
+	var changes []dbObjectChange
+	lastSequenceID := sequenceID
+	err := PagingGetObjectFunc(ctx, p.client, p.paging, &ibclient.DbObjects{}, "", dbObjectsSearchFields(sequenceID), func(page []dbObjectChange) error {
+		for _, change := range page {
+			if change.LastSequenceID != "" {
+				lastSequenceID = change.LastSequenceID
+			}
+		}
+		changes = append(changes, page...)
+		return nil
+	})
+	if err != nil && !isNotFoundError(err) {
+		return nil, "", err
+	}
+	return changes, lastSequenceID, nil
+}
+
+// currentSequenceID returns the sequence ID of the Grid database, or the given one if nothing
+// changed since. WAPI reports the last sequence ID with every object it returns, so a single
+// object is requested, a positive _max_results truncates the result instead of failing.
+func (p *Provider) currentSequenceID(ctx context.Context, sequenceID string) (string, error) {
+	obj := &ibclient.DbObjects{}
+	obj.SetReturnFields([]string{"last_sequence_id"})
+	searchFields := dbObjectsSearchFields(sequenceID)
+	searchFields["_max_results"] = "1"
+	var res []dbObjectChange
+	err := getPage(ctx, connectorWithContext(ctx, p.client), p.paging, obj, "", ibclient.NewQueryParams(false, searchFields), &res)
+	if err != nil && !isNotFoundError(err) {
+		return "", err
+	}
+	for _, change := range res {
+		if change.LastSequenceID != "" {
+			sequenceID = change.LastSequenceID
+		}
+	}
+	return sequenceID, nil
+}
+
+// dbObjectsSearchFields are the search fields of the objects changed since the sequence ID
+func dbObjectsSearchFields(sequenceID string) map[string]string {
+	searchFields := map[string]string{"object_types": strings.Join(syncObjectTypes, ",")}
+	if sequenceID != "" {
+		searchFields["start_sequence_id"] = sequenceID
+	}
+	return searchFields
+}
+
+// syncRecords brings the records cache up to date with the changes in the Grid. The records
+// of the changed names are fetched again and merged into the cached records of their zones,
+// which evicts the records of deleted objects. Changes which cannot be attributed to a name,
+// e.g. deletes of objects not changed since the start, drop the whole cache, changes of PTR
+// records drop the reverse zones.
+func (p *Provider) syncRecords(ctx context.Context, zones []ibclient.ZoneAuth, extAttrs ibclient.EA) error {
+	s := p.incremental
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.lastFullSync.IsZero() || now.Sub(s.lastFullSync) >= s.interval {
+		// the sequence ID is taken before the records are fetched, so no change is missed. The
+		// changes since the last sync are not needed, as all records are fetched again.
+		sequenceID, err := p.currentSequenceID(ctx, s.sequenceID)
+		if err != nil {
+			return fmt.Errorf("could not fetch changed objects: %w", err)
+		}
+		log.Infof("full resync of the records, sequence ID '%s'", sequenceID)
+		p.cache.invalidate(p.config.View)
+		s.sequenceID, s.lastSync, s.lastFullSync = sequenceID, now, now
+		return nil
+	}
+	if now.Sub(s.lastSync) < s.ttl {
+		return nil
+	}
+
+	changes, sequenceID, err := p.dbObjectChanges(ctx, s.sequenceID)
+	if err != nil {
+		return fmt.Errorf("could not fetch changed objects: %w", err)
+	}
+	log.Debugf("incremental sync of %d changed objects since sequence ID '%s'", len(changes), s.sequenceID)
+	zonePtrs := zonePointerConverter(zones)
+	changedNames := map[string]*ibclient.ZoneAuth{}
+	changedPTR := false
+	for i := range changes {
+		name, view, ok := s.record(&changes[i])
+		if !ok {
+			log.Infof("could not attribute a change of '%s' to a record, dropping the cache", changes[i].ObjectType)
+			p.cache.invalidate(p.config.View)
+			s.sequenceID, s.lastSync = sequenceID, now
+			return nil
+		}
+		if p.config.View != "" && view != "" && view != p.config.View {
+			continue
+		}
+		if changes[i].ObjectType == "record:ptr" {
+			// PTR records are cached by the name they point to, in the reverse zones
+			changedPTR = true
+			continue
+		}
+		if zone := p.findZone(zonePtrs, name); zone != nil {
+			changedNames[name] = zone
+		}
+	}
+	if changedPTR {
+		var reverseZones []string
+		for _, zone := range zones {
+			if _, _, err := net.ParseCIDR(zone.Fqdn); err == nil {
+				reverseZones = append(reverseZones, zone.Fqdn)
+			}
+		}
+		if len(reverseZones) > 0 {
+			p.cache.invalidate(p.config.View, reverseZones...)
+		}
+	}
+	for name, zone := range changedNames {
+		results, err := p.fetchConcurrently(ctx, p.zoneRecordFetches(*zone, extAttrs, name))
+		if err != nil {
+			return err
+		}
+		var endpoints []*endpoint.Endpoint
+		for _, result := range results {
+			endpoints = append(endpoints, result...)
+		}
+		p.cache.merge(p.config.View, zone.Fqdn, name, endpoints)
+	}
+	p.cache.touch(p.config.View)
+	s.sequenceID, s.lastSync = sequenceID, now
+	return nil
+}
@@ -0,0 +1,271 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// fetchedNames returns the names whose records have been fetched on their own
+func (client *mockIBConnector) fetchedNames() map[string]bool {
+	names := map[string]bool{}
+	for _, req := range client.getObjectRequests {
+		if name := req.url.Query().Get("name"); name != "" && req.obj != "zone_auth" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// dbObjectsRequests returns the start sequence IDs of the db_objects requests
+func (client *mockIBConnector) dbObjectsRequests() []string {
+	var starts []string
+	for _, req := range client.getObjectRequests {
+		if req.obj == "db_objects" {
+			starts = append(starts, req.url.Query().Get("start_sequence_id"))
+		}
+	}
+	return starts
+}
+
+func newIncrementalSyncTestProvider() (*Provider, *mockIBConnector, *time.Time) {
+	providerCfg, client := newCacheTestProvider()
+	providerCfg.incremental = newRecordsSync(time.Hour, time.Minute)
+	now := time.Now()
+	providerCfg.cache.now = func() time.Time { return now }
+	providerCfg.incremental.now = func() time.Time { return now }
+	client.dbObjectChanges = []dbObjectChange{
+		{LastSequenceID: "1", ObjectType: recordA, Object: json.RawMessage(`{"_ref": "record:a/ZG5z:a.example.com/default"}`)},
+	}
+	return providerCfg, client, &now
+}
+
+func TestInfobloxRecordsIncrementalSync(t *testing.T) {
+	providerCfg, client, now := newIncrementalSyncTestProvider()
+
+	// the first sync is a full resync from the current sequence ID
+	_, err := providerCfg.Records(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{""}, client.dbObjectsRequests())
+	assert.Equal(t, map[string]bool{"example.com": true, "other.com": true}, client.fetchedZones())
+
+	// within the cache TTL the records are served from the cache
+	client.getObjectRequests = nil
+	_, err = providerCfg.Records(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, client.dbObjectsRequests())
+	assert.Empty(t, client.fetchedZones())
+
+	// then only the records of the changed names are fetched and merged
+	*client.mockInfobloxObjects = append(*client.mockInfobloxObjects,
+		createMockInfobloxObjectWithZone("b.example.com", endpoint.RecordTypeA, "1.1.1.2", "example.com"))
+	client.dbObjectChanges = append(client.dbObjectChanges, dbObjectChange{
+		LastSequenceID: "2", ObjectType: recordA, Object: json.RawMessage(`{"name": "b.example.com", "view": "default"}`),
+	})
+	*now = now.Add(time.Minute)
+	client.getObjectRequests = nil
+	actual, err := providerCfg.Records(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, client.dbObjectsRequests())
+	assert.Equal(t, map[string]bool{"b.example.com": true}, client.fetchedNames())
+	validateEndpoints(t, actual, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "1.1.1.1"),
+		endpoint.NewEndpoint("a.other.com", endpoint.RecordTypeA, "2.2.2.2"),
+		endpoint.NewEndpoint("b.example.com", endpoint.RecordTypeA, "1.1.1.2"),
+	})
+
+	// the cache is dropped every full resync interval
+	*now = now.Add(time.Hour)
+	client.getObjectRequests = nil
+	_, err = providerCfg.Records(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2"}, client.dbObjectsRequests())
+	assert.Equal(t, map[string]bool{"example.com": true, "other.com": true}, client.fetchedZones())
+}
+
+// dbObjectsReturnFields returns the return fields of the db_objects requests
+func (client *mockIBConnector) dbObjectsReturnFields() []string {
+	var fields []string
+	for _, req := range client.getObjectRequests {
+		if req.obj == "db_objects" {
+			fields = append(fields, req.url.Query().Get("_return_fields"))
+		}
+	}
+	return fields
+}
+
+func TestInfobloxRecordsIncrementalSyncSequenceID(t *testing.T) {
+	providerCfg, client, now := newIncrementalSyncTestProvider()
+
+	// a full resync only needs the current sequence ID, not the changed objects
+	_, err := providerCfg.Records(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"last_sequence_id"}, client.dbObjectsReturnFields())
+	assert.Equal(t, "1", providerCfg.incremental.sequenceID)
+
+	client.dbObjectChanges = append(client.dbObjectChanges, dbObjectChange{
+		LastSequenceID: "2", ObjectType: recordA, Object: json.RawMessage(`{"name": "a.example.com", "view": "default"}`),
+	})
+	*now = now.Add(time.Minute)
+	client.getObjectRequests = nil
+	_, err = providerCfg.Records(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"last_sequence_id,object,object_type,unique_id"}, client.dbObjectsReturnFields())
+
+	*now = now.Add(time.Hour)
+	client.getObjectRequests = nil
+	_, err = providerCfg.Records(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"last_sequence_id"}, client.dbObjectsReturnFields())
+	assert.Equal(t, "2", providerCfg.incremental.sequenceID)
+}
+
+func TestInfobloxRecordsIncrementalSyncFirstSequenceID(t *testing.T) {
+	providerCfg, client, _ := newIncrementalSyncTestProvider()
+	providerCfg.paging.PageSize = 1
+	client.dbObjectChanges = append(client.dbObjectChanges,
+		dbObjectChange{LastSequenceID: "2", ObjectType: recordA, Object: json.RawMessage(`{"name": "b.example.com", "view": "default"}`)},
+		dbObjectChange{LastSequenceID: "3", ObjectType: recordA, Object: json.RawMessage(`{"name": "c.example.com", "view": "default"}`)},
+	)
+
+	// without a sequence ID, a single object of the database is requested, not all of them
+	_, err := providerCfg.Records(context.Background())
+	require.NoError(t, err)
+	var dbObjects []getObjectRequest
+	for _, req := range client.getObjectRequests {
+		if req.obj == "db_objects" {
+			dbObjects = append(dbObjects, *req)
+		}
+	}
+	require.Len(t, dbObjects, 1)
+	assert.Equal(t, "1", dbObjects[0].url.Query().Get("_max_results"))
+	assert.False(t, dbObjects[0].url.Query().Has("_paging"))
+	assert.Equal(t, "3", providerCfg.incremental.sequenceID)
+}
+
+func TestInfobloxRecordsIncrementalSyncNameRegEx(t *testing.T) {
+	providerCfg, client, now := newIncrementalSyncTestProvider()
+	client.requestBuilder = *NewExtendedRequestBuilder(0, "", "^a\\.")
+
+	_, err := providerCfg.Records(context.Background())
+	require.NoError(t, err)
+	*now = now.Add(time.Minute)
+	_, err = providerCfg.Records(context.Background())
+	require.NoError(t, err)
+
+	// db_objects has no name field, the name filter applies to the records only
+	var dbObjects int
+	for _, req := range client.getObjectRequests {
+		if req.obj == "db_objects" {
+			dbObjects++
+			assert.False(t, req.url.Query().Has("name~"))
+		} else if req.obj == recordA {
+			assert.Equal(t, "^a\\.", req.url.Query().Get("name~"))
+		}
+	}
+	assert.Equal(t, 2, dbObjects)
+}
+
+func TestInfobloxRecordsIncrementalSyncUnknownChange(t *testing.T) {
+	providerCfg, client, now := newIncrementalSyncTestProvider()
+	_, err := providerCfg.Records(context.Background())
+	require.NoError(t, err)
+
+	client.dbObjectChanges = append(client.dbObjectChanges, dbObjectChange{
+		LastSequenceID: "2", ObjectType: recordA, Object: json.RawMessage(`"None"`),
+	})
+	*now = now.Add(time.Minute)
+	client.getObjectRequests = nil
+	_, err = providerCfg.Records(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"example.com": true, "other.com": true}, client.fetchedZones())
+	assert.Equal(t, "2", providerCfg.incremental.sequenceID)
+}
+
+func TestInfobloxRecordsIncrementalSyncDelete(t *testing.T) {
+	providerCfg, client, now := newIncrementalSyncTestProvider()
+	_, err := providerCfg.Records(context.Background())
+	require.NoError(t, err)
+
+	b := createMockInfobloxObjectWithZone("b.example.com", endpoint.RecordTypeA, "1.1.1.2", "example.com")
+	*client.mockInfobloxObjects = append(*client.mockInfobloxObjects, b)
+	client.dbObjectChanges = append(client.dbObjectChanges, dbObjectChange{
+		LastSequenceID: "2", ObjectType: recordA, UniqueID: "b", Object: json.RawMessage(`{"name": "b.example.com", "view": "default"}`),
+	})
+	*now = now.Add(time.Minute)
+	_, err = providerCfg.Records(context.Background())
+	require.NoError(t, err)
+
+	// the deleted object is attributed to the name it had, only its records are fetched again
+	*client.mockInfobloxObjects = (*client.mockInfobloxObjects)[:len(*client.mockInfobloxObjects)-1]
+	client.dbObjectChanges = append(client.dbObjectChanges, dbObjectChange{
+		LastSequenceID: "3", ObjectType: recordA, UniqueID: "b", Object: json.RawMessage(`"None"`),
+	})
+	*now = now.Add(time.Minute)
+	client.getObjectRequests = nil
+	actual, err := providerCfg.Records(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"example.com": true}, client.fetchedZones())
+	assert.Equal(t, map[string]bool{"b.example.com": true}, client.fetchedNames())
+	validateEndpoints(t, actual, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.example.com", endpoint.RecordTypeA, "1.1.1.1"),
+		endpoint.NewEndpoint("a.other.com", endpoint.RecordTypeA, "2.2.2.2"),
+	})
+	assert.Empty(t, providerCfg.incremental.records)
+}
+
+func TestInfobloxRefreshIncrementalSync(t *testing.T) {
+	providerCfg, client, _ := newIncrementalSyncTestProvider()
+	_, err := providerCfg.Records(context.Background())
+	require.NoError(t, err)
+
+	client.getObjectRequests = nil
+	require.NoError(t, providerCfg.Refresh(context.Background()))
+	assert.Equal(t, []string{"1"}, client.dbObjectsRequests())
+	assert.Equal(t, map[string]bool{"example.com": true, "other.com": true}, client.fetchedZones())
+}
+
+func TestDBObjectChangeRecord(t *testing.T) {
+	tests := []struct {
+		object string
+		name   string
+		view   string
+		ok     bool
+	}{
+		{object: `{"name": "a.example.com", "view": "default"}`, name: "a.example.com", view: "default", ok: true},
+		{object: `{"_ref": "record:cname/ZG5zLmJpbmRfY25hbWUk:www.example.com/internal"}`, name: "www.example.com", view: "internal", ok: true},
+		{object: `{"_ref": "record:a/ZG5z"}`},
+		{object: `"None"`},
+	}
+	for _, tt := range tests {
+		change := dbObjectChange{Object: json.RawMessage(tt.object)}
+		name, view, ok := change.record()
+		assert.Equal(t, tt.name, name, tt.object)
+		assert.Equal(t, tt.view, view, tt.object)
+		assert.Equal(t, tt.ok, ok, tt.object)
+	}
+}
@@ -105,3 +105,10 @@ func AsInt64(i *uint32) int64 {
 	}
 	return int64(*i)
 }
+
+func AsBool(b *bool) bool {
+	if b == nil {
+		return false
+	}
+	return *b
+}
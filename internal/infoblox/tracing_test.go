@@ -0,0 +1,140 @@
+package infoblox
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+)
+
+// recordSpans records the spans ended while the test runs
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+// spansByName returns the ended spans by their name
+func spansByName(recorder *tracetest.SpanRecorder) map[string][]sdktrace.ReadOnlySpan {
+	spans := map[string][]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = append(spans[span.Name()], span)
+	}
+	return spans
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestInfobloxRecordsSpans(t *testing.T) {
+	recorder := recordSpans(t)
+	providerCfg, _ := newCacheTestProvider()
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "GET /records")
+
+	_, err := providerCfg.Records(ctx)
+	require.NoError(t, err)
+	parent.End()
+
+	spans := spansByName(recorder)
+	require.Len(t, spans["infoblox.zones"], 1)
+	zones := spans["infoblox.zones"][0]
+	assert.Equal(t, parent.SpanContext().SpanID(), zones.Parent().SpanID())
+	assert.Equal(t, int64(2), spanAttribute(zones, "infoblox.zones").AsInt64())
+
+	// a page of the zones and one page per record type and zone, all in the trace of the request
+	pages := spans["infoblox.page"]
+	require.NotEmpty(t, pages)
+	objectTypes := map[string]bool{}
+	for _, page := range pages {
+		assert.Equal(t, parent.SpanContext().TraceID(), page.SpanContext().TraceID())
+		objectTypes[spanAttribute(page, "infoblox.object_type").AsString()] = true
+	}
+	assert.True(t, objectTypes["zone_auth"])
+	assert.True(t, objectTypes["record:a"])
+}
+
+func TestInfobloxApplyChangesSpans(t *testing.T) {
+	recorder := recordSpans(t)
+	client := &mockIBConnector{
+		mockInfobloxZones: &[]ibclient.ZoneAuth{
+			createMockInfobloxZone("example.com"),
+		},
+		mockInfobloxObjects: &[]ibclient.IBObject{
+			createMockInfobloxObject("old.example.com", endpoint.RecordTypeA, "1.1.1.1"),
+		},
+		createErrors: map[string]error{"fail.example.com": errors.New("WAPI request error: 400('400 Bad Request')\nContents:\n")},
+	}
+	providerCfg := newInfobloxProvider(endpoint.NewDomainFilter([]string{"example.com"}), provider.NewZoneIDFilter([]string{""}), "", false, false, client)
+	providerCfg.config.ContinueOnError = true
+
+	err := providerCfg.ApplyChanges(context.Background(), &plan.Changes{
+		Create: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("new.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+			endpoint.NewEndpoint("fail.example.com", endpoint.RecordTypeA, "9.9.9.9"),
+		},
+		Delete: []*endpoint.Endpoint{endpoint.NewEndpoint("old.example.com", endpoint.RecordTypeA, "1.1.1.1")},
+	})
+	require.Error(t, err)
+
+	spans := spansByName(recorder)
+	require.Len(t, spans["infoblox.create"], 2)
+	require.Len(t, spans["infoblox.delete"], 1)
+	for _, span := range spans["infoblox.create"] {
+		assert.Equal(t, "example.com", spanAttribute(span, "infoblox.zone").AsString())
+		assert.Equal(t, endpoint.RecordTypeA, spanAttribute(span, "dns.record_type").AsString())
+		if spanAttribute(span, "dns.name").AsString() == "fail.example.com" {
+			assert.Equal(t, codes.Error, span.Status().Code)
+		} else {
+			assert.Equal(t, codes.Unset, span.Status().Code)
+		}
+	}
+
+	// the lookups of the records are children of the changes
+	changeSpans := map[trace.SpanID]bool{}
+	for _, span := range append(spans["infoblox.create"], spans["infoblox.delete"]...) {
+		changeSpans[span.SpanContext().SpanID()] = true
+	}
+	require.NotEmpty(t, spans["infoblox.record_set"])
+	for _, span := range spans["infoblox.record_set"] {
+		assert.True(t, changeSpans[span.Parent().SpanID()])
+	}
+}
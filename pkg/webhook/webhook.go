@@ -19,6 +19,7 @@ Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
 */
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -34,9 +35,12 @@ import (
 const (
 	contentTypeHeader     = "Content-Type"
 	contentTypePlaintext  = "text/plain"
+	contentTypeJSON       = "application/json"
 	acceptHeader          = "Accept"
 	varyHeader            = "Vary"
 	healthPath            = "/healthz"
+	livePath              = "/livez"
+	readyPath             = "/readyz"
 	logFieldRequestPath   = "requestPath"
 	logFieldRequestMethod = "requestMethod"
 	logFieldError         = "error"
@@ -53,17 +57,100 @@ func New(provider provider.Provider) *Webhook {
 	return &p
 }
 
-// Health handles the health request
-func Health(next http.Handler) http.Handler {
+// HealthReporter is implemented by providers which can report a degraded state, e.g. while
+// their backend is unavailable
+type HealthReporter interface {
+	Health() error
+}
+
+// ReadinessReporter is implemented by providers which check their backend in the background.
+// RunReadinessChecks runs the checks until the context is cancelled. Readiness returns the details
+// of the last checks, and an error unless they all succeeded.
+type ReadinessReporter interface {
+	RunReadinessChecks(ctx context.Context)
+	Readiness() (interface{}, error)
+}
+
+// probeResponse is the body of the liveness and readiness probes
+type probeResponse struct {
+	Status string      `json:"status"`
+	Error  string      `json:"error,omitempty"`
+	Checks interface{} `json:"checks,omitempty"`
+}
+
+// StartReadinessChecks runs the readiness checks of the provider in the background, until the
+// context is cancelled
+func (p *Webhook) StartReadinessChecks(ctx context.Context) {
+	if reporter, ok := p.provider.(ReadinessReporter); ok {
+		go reporter.RunReadinessChecks(ctx)
+	}
+}
+
+// Health handles the probes:
+// - /healthz responds with 503 while the provider reports a degraded state
+// - /livez responds with 200 as long as the process serves requests
+// - /readyz responds with 503 until the readiness checks of the provider succeed
+func (p *Webhook) Health(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == healthPath {
-			w.WriteHeader(http.StatusOK)
-			return
+		switch r.URL.Path {
+		case healthPath:
+			p.health(w, r)
+		case livePath:
+			writeProbeResponse(w, r, http.StatusOK, probeResponse{Status: "ok"})
+		case readyPath:
+			p.ready(w, r)
+		default:
+			next.ServeHTTP(w, r)
 		}
-		next.ServeHTTP(w, r)
 	})
 }
 
+func (p *Webhook) health(w http.ResponseWriter, r *http.Request) {
+	reporter, ok := p.provider.(HealthReporter)
+	if !ok {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if err := reporter.Health(); err != nil {
+		requestLog(r).WithField(logFieldError, err).Warn("provider is degraded")
+		w.Header().Set(contentTypeHeader, contentTypePlaintext)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		if _, writeError := fmt.Fprint(w, err.Error()); writeError != nil {
+			requestLog(r).WithField(logFieldError, writeError).Fatalf("error writing error message to response writer")
+		}
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// ready reports the readiness of the provider. Providers without readiness checks are ready
+// unless they report a degraded state.
+func (p *Webhook) ready(w http.ResponseWriter, r *http.Request) {
+	var (
+		checks interface{}
+		err    error
+	)
+	if reporter, ok := p.provider.(ReadinessReporter); ok {
+		checks, err = reporter.Readiness()
+	} else if reporter, ok := p.provider.(HealthReporter); ok {
+		err = reporter.Health()
+	}
+	if err != nil {
+		requestLog(r).WithField(logFieldError, err).Debug("provider is not ready")
+		writeProbeResponse(w, r, http.StatusServiceUnavailable, probeResponse{Status: "not ready", Error: err.Error(), Checks: checks})
+		return
+	}
+	writeProbeResponse(w, r, http.StatusOK, probeResponse{Status: "ready", Checks: checks})
+}
+
+func writeProbeResponse(w http.ResponseWriter, r *http.Request, status int, response probeResponse) {
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		requestLog(r).WithField(logFieldError, err).Error("error encoding probe response")
+	}
+}
+
 func (p *Webhook) contentTypeHeaderCheck(w http.ResponseWriter, r *http.Request) error {
 	return p.headerCheck(true, w, r)
 }
@@ -124,6 +211,9 @@ func (p *Webhook) headerCheck(isContentType bool, w http.ResponseWriter, r *http
 
 // Records handles the get request for records
 func (p *Webhook) Records(w http.ResponseWriter, r *http.Request) {
+	w, r, endSpan := startSpan(w, r, "/records")
+	defer endSpan()
+
 	if err := p.acceptHeaderCheck(w, r); err != nil {
 		requestLog(r).WithField(logFieldError, err).Error("accept header check failed")
 		return
@@ -151,6 +241,9 @@ func (p *Webhook) Records(w http.ResponseWriter, r *http.Request) {
 
 // ApplyChanges handles the post request for record changes
 func (p *Webhook) ApplyChanges(w http.ResponseWriter, r *http.Request) {
+	w, r, endSpan := startSpan(w, r, "/records")
+	defer endSpan()
+
 	if err := p.contentTypeHeaderCheck(w, r); err != nil {
 		requestLog(r).WithField(logFieldError, err).Error("content type header check failed")
 		return
@@ -173,8 +266,54 @@ func (p *Webhook) ApplyChanges(w http.ResponseWriter, r *http.Request) {
 	requestLog(r).Debugf("requesting apply changes, create: %d , updateOld: %d, updateNew: %d, delete: %d",
 		len(changes.Create), len(changes.UpdateOld), len(changes.UpdateNew), len(changes.Delete))
 	if err := p.provider.ApplyChanges(ctx, &changes); err != nil {
+		requestLog(r).WithField(logFieldError, err).Error("error applying changes")
+		// errors reporting the failed changes in detail are returned as JSON
+		var report json.Marshaler
+		if errors.As(err, &report) {
+			if out, marshalErr := report.MarshalJSON(); marshalErr == nil {
+				w.Header().Set(contentTypeHeader, contentTypeJSON)
+				w.WriteHeader(http.StatusInternalServerError)
+				if _, writeError := w.Write(out); writeError != nil {
+					requestLog(r).WithField(logFieldError, writeError).Fatalf("error writing error report to response writer")
+				}
+				return
+			}
+		}
+		w.Header().Set(contentTypeHeader, contentTypePlaintext)
+		w.WriteHeader(http.StatusInternalServerError)
+		// errors without a report, e.g. failed zone fetches, are returned as plain text
+		if _, writeError := fmt.Fprint(w, err.Error()); writeError != nil {
+			requestLog(r).WithField(logFieldError, writeError).Fatalf("error writing error message to response writer")
+		}
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Refresher is implemented by providers which cache their records
+type Refresher interface {
+	Refresh(ctx context.Context) error
+}
+
+// Refresh handles the post request for refreshing the cached records
+func (p *Webhook) Refresh(w http.ResponseWriter, r *http.Request) {
+	w, r, endSpan := startSpan(w, r, "/refresh")
+	defer endSpan()
+
+	refresher, ok := p.provider.(Refresher)
+	if !ok {
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+
+	requestLog(r).Debug("requesting refresh of the records")
+	if err := refresher.Refresh(r.Context()); err != nil {
+		requestLog(r).WithField(logFieldError, err).Error("error refreshing records")
 		w.Header().Set(contentTypeHeader, contentTypePlaintext)
 		w.WriteHeader(http.StatusInternalServerError)
+		if _, writeError := fmt.Fprint(w, err.Error()); writeError != nil {
+			requestLog(r).WithField(logFieldError, writeError).Fatalf("error writing error message to response writer")
+		}
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -182,6 +321,9 @@ func (p *Webhook) ApplyChanges(w http.ResponseWriter, r *http.Request) {
 
 // AdjustEndpoints handles the post request for adjusting endpoints
 func (p *Webhook) AdjustEndpoints(w http.ResponseWriter, r *http.Request) {
+	w, r, endSpan := startSpan(w, r, "/adjustendpoints")
+	defer endSpan()
+
 	if err := p.contentTypeHeaderCheck(w, r); err != nil {
 		log.Errorf("content type header check failed, request method: %s, request path: %s", r.Method, r.URL.Path)
 		return
@@ -222,6 +364,9 @@ func (p *Webhook) AdjustEndpoints(w http.ResponseWriter, r *http.Request) {
 }
 
 func (p *Webhook) Negotiate(w http.ResponseWriter, r *http.Request) {
+	w, r, endSpan := startSpan(w, r, "/")
+	defer endSpan()
+
 	if err := p.acceptHeaderCheck(w, r); err != nil {
 		requestLog(r).WithField(logFieldError, err).Error("accept header check failed")
 		return
@@ -0,0 +1,102 @@
+package webhook
+
+/*
+Copyright 2024 The external-dns-infoblox-webhook Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+)
+
+// failingProvider fails every apply with its error
+type failingProvider struct {
+	provider.BaseProvider
+	err error
+}
+
+func (p *failingProvider) Records(context.Context) ([]*endpoint.Endpoint, error) {
+	return nil, nil
+}
+
+func (p *failingProvider) ApplyChanges(context.Context, *plan.Changes) error {
+	return p.err
+}
+
+// reportError is an error reporting the failed changes as JSON
+type reportError struct{}
+
+func (e *reportError) Error() string {
+	return "1 of 1 changes failed"
+}
+
+func (e *reportError) MarshalJSON() ([]byte, error) {
+	return []byte(`{"message":"1 of 1 changes failed"}`), nil
+}
+
+func TestApplyChangesError(t *testing.T) {
+	tests := []struct {
+		name                string
+		err                 error
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "report",
+			err:                 &reportError{},
+			expectedContentType: contentTypeJSON,
+			expectedBody:        `{"message":"1 of 1 changes failed"}`,
+		},
+		{
+			name:                "wrapped report",
+			err:                 fmt.Errorf("could not apply changes: %w", &reportError{}),
+			expectedContentType: contentTypeJSON,
+			expectedBody:        `{"message":"1 of 1 changes failed"}`,
+		},
+		{
+			name:                "plain error",
+			err:                 errors.New("could not fetch zones"),
+			expectedContentType: contentTypePlaintext,
+			expectedBody:        "could not fetch zones",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/records", strings.NewReader(`{"Create":[]}`))
+			r.Header.Set(contentTypeHeader, string(mediaTypeVersion1))
+			w := httptest.NewRecorder()
+
+			New(&failingProvider{err: tt.err}).ApplyChanges(w, r)
+
+			assert.Equal(t, http.StatusInternalServerError, w.Code)
+			assert.Equal(t, tt.expectedContentType, w.Header().Get(contentTypeHeader))
+			assert.Equal(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}